@@ -0,0 +1,98 @@
+package mcmap
+
+import (
+	"github.com/silvasur/gonbt/nbt"
+)
+
+// forChunksIn calls f for all available chunks intersecting the area from x0, z0 to x1, z1 (global block coordinates, inclusive).
+// Chunks that were not loaded before are unloaded afterwards.
+func (reg *Region) forChunksIn(x0, z0, x1, z1 int, f func(c *Chunk) error) error {
+	cx0, cz0, _, _ := BlockToChunk(x0, z0)
+	cx1, cz1, _, _ := BlockToChunk(x1, z1)
+
+	for cx := cx0; cx <= cx1; cx++ {
+		for cz := cz0; cz <= cz1; cz++ {
+			wasLoaded := reg.chunkLoaded(cx, cz)
+			c, err := reg.Chunk(cx, cz)
+			switch err {
+			case nil:
+			case NotAvailable:
+				continue
+			default:
+				return err
+			}
+
+			err = f(c)
+			if !wasLoaded {
+				if uerr := c.MarkUnused(); uerr != nil && err == nil {
+					err = uerr
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// chunkArea returns the part of the area from x0, y0, z0 to x1, y1, z1 (global coordinates, inclusive) inside the chunk
+// in chunk coordinates. ok is false, if the area does not intersect the chunk.
+func (c *Chunk) chunkArea(x0, y0, z0, x1, y1, z1 int) (minX, minY, minZ, maxX, maxY, maxZ int, ok bool) {
+	bx, bz := ChunkToBlock(int(c.x), int(c.z), 0, 0)
+	minX, maxX = maxInt(x0-bx, 0), minInt(x1-bx, ChunkSizeXZ-1)
+	minZ, maxZ = maxInt(z0-bz, 0), minInt(z1-bz, ChunkSizeXZ-1)
+	minY, maxY = maxInt(y0, c.minY), minInt(y1, c.MaxY())
+	ok = minX <= maxX && minY <= maxY && minZ <= maxZ
+	return
+}
+
+// copyBlock returns a copy of the block without scheduled updates. The tile entity is copied, including its nested data.
+func copyBlock(blk Block) Block {
+	blk.TileEntity = deepCopyCompound(blk.TileEntity)
+	blk.Tick = nil
+	return blk
+}
+
+// translated returns a copy of the entity (sharing no NBT data with it) moved by dx, dy, dz blocks.
+// Hanging entities (item frames, paintings) are moved too.
+func (e *Entity) translated(dx, dy, dz int) *Entity {
+	cp := *e
+	cp.NBT = deepCopyCompound(e.NBT)
+	cp.Pos[0] += float64(dx)
+	cp.Pos[1] += float64(dy)
+	cp.Pos[2] += float64(dz)
+
+	for name, d := range map[string]int{"TileX": dx, "TileY": dy, "TileZ": dz} {
+		if v, err := cp.NBT.GetInt(name); err == nil {
+			cp.NBT[name] = nbt.NewIntTag(v + int32(d))
+		}
+	}
+	return &cp
+}
+
+// addEntity adds an entity to the chunk containing its position, if that chunk is available.
+// The ID of the entity is converted from the format of the given data version to the one of the chunk.
+func (reg *Region) addEntity(e *Entity, dataVersion int32) error {
+	x, _, z := e.BlockPos()
+	cx, cz, _, _ := BlockToChunk(x, z)
+
+	wasLoaded := reg.chunkLoaded(cx, cz)
+	c, err := reg.Chunk(cx, cz)
+	switch err {
+	case nil:
+	case NotAvailable:
+		return nil
+	default:
+		return err
+	}
+
+	convertEntityID(e, dataVersion, c.dataVersion)
+	c.Entities = append(c.Entities, e)
+	c.MarkModified()
+	if !wasLoaded {
+		return c.MarkUnused()
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package mcmap
+
+import (
+	"image/color"
+	"sync"
+)
+
+// Precipitation is the kind of weather in a biome, when it rains.
+type Precipitation int
+
+const (
+	PrecipNone Precipitation = iota // It doesn't rain (e.g. desert)
+	PrecipRain
+	PrecipSnow
+)
+
+func (p Precipitation) String() string {
+	switch p {
+	case PrecipNone:
+		return "none"
+	case PrecipRain:
+		return "rain"
+	case PrecipSnow:
+		return "snow"
+	}
+	return "(unknown)"
+}
+
+// BiomeProperties describes the climate of a biome.
+type BiomeProperties struct {
+	Temperature   float32       // Temperature at sea level. Below 0.15 water freezes and it snows.
+	Downfall      float32       // Humidity of the biome (0 - 1).
+	Precipitation Precipitation // Weather at sea level. Use PrecipitationAt for other heights.
+	GrassColor    color.RGBA    // Color used to tint grass.
+	FoliageColor  color.RGBA    // Color used to tint leaves and vines.
+	WaterColor    color.RGBA    // Color used to tint water.
+	Ocean         bool          // The biome is an ocean.
+	Snowy         bool          // The biome is a cold, snow covered biome.
+	Nether        bool          // The biome belongs to the nether.
+}
+
+// UnknownBiomeProperties are returned by Biome.Properties for biomes without registered properties (the plains climate).
+var UnknownBiomeProperties = BiomeProperties{
+	Temperature:   0.8,
+	Downfall:      0.4,
+	Precipitation: PrecipRain,
+	GrassColor:    grassColor(0.8, 0.4),
+	FoliageColor:  foliageColor(0.8, 0.4),
+	WaterColor:    defaultWaterColor,
+}
+
+// The properties of the biomes. Biomes with dynamic IDs get registered while chunks are read (see BiomeByName),
+// so the map is guarded by a lock.
+var (
+	biomeProperties     = make(map[Biome]*BiomeProperties)
+	biomePropertiesLock sync.RWMutex
+)
+
+// RegisterBiomeProperties sets the properties of a biome, replacing the old ones.
+func RegisterBiomeProperties(bio Biome, props BiomeProperties) {
+	biomePropertiesLock.Lock()
+	defer biomePropertiesLock.Unlock()
+	biomeProperties[bio] = &props
+}
+
+// Properties returns the properties of the biome. For unknown biomes UnknownBiomeProperties are returned.
+func (b Biome) Properties() BiomeProperties {
+	biomePropertiesLock.RLock()
+	props := biomeProperties[b]
+	biomePropertiesLock.RUnlock()
+
+	if props != nil {
+		return *props
+	}
+	return UnknownBiomeProperties
+}
+
+// TemperatureAt returns the temperature at the height y. Above Y=80 it gets colder.
+//
+// Minecraft adds some noise to the temperature, so this is only an approximation near the snow line.
+func (p BiomeProperties) TemperatureAt(y int) float32 {
+	if y <= 80 {
+		return p.Temperature
+	}
+	return p.Temperature - float32(y-80)*0.05/40
+}
+
+// PrecipitationAt returns the weather at the height y (rain turns to snow high up in the mountains).
+func (p BiomeProperties) PrecipitationAt(y int) Precipitation {
+	if p.Precipitation == PrecipNone {
+		return PrecipNone
+	}
+	if p.TemperatureAt(y) < 0.15 {
+		return PrecipSnow
+	}
+	return PrecipRain
+}
+
+// Freezes checks, if water freezes to ice and snow stays on the ground at the height y.
+func (p BiomeProperties) Freezes(y int) bool {
+	return p.TemperatureAt(y) < 0.15
+}
+
+var defaultWaterColor = rgb(0x3f76e4)
+
+// The corners of the grass and foliage color maps: hot and wet, hot and dry, cold.
+var (
+	grassColorCorners   = [3]color.RGBA{rgb(0x47cd33), rgb(0xbfb755), rgb(0x80b497)}
+	foliageColorCorners = [3]color.RGBA{rgb(0x1abf00), rgb(0xaea42a), rgb(0x60a17b)}
+)
+
+// colormapColor looks up a color in the triangular grass / foliage color maps of Minecraft
+// by interpolating between the corners.
+func colormapColor(corners [3]color.RGBA, temp, downfall float32) color.RGBA {
+	temp = clamp01(temp)
+	downfall = clamp01(downfall) * temp
+
+	weights := [3]float32{downfall, temp - downfall, 1 - temp}
+	var r, g, b float32
+	for i, c := range corners {
+		r += weights[i] * float32(c.R)
+		g += weights[i] * float32(c.G)
+		b += weights[i] * float32(c.B)
+	}
+	return color.RGBA{byte(r + 0.5), byte(g + 0.5), byte(b + 0.5), 0xff}
+}
+
+func clamp01(f float32) float32 {
+	switch {
+	case f < 0:
+		return 0
+	case f > 1:
+		return 1
+	}
+	return f
+}
+
+func grassColor(temp, downfall float32) color.RGBA {
+	return colormapColor(grassColorCorners, temp, downfall)
+}
+
+func foliageColor(temp, downfall float32) color.RGBA {
+	return colormapColor(foliageColorCorners, temp, downfall)
+}
+
+// averageColor mixes two colors half and half (like the grass of roofed forests).
+func averageColor(a, b color.RGBA) color.RGBA {
+	return color.RGBA{byte((int(a.R) + int(b.R)) / 2), byte((int(a.G) + int(b.G)) / 2), byte((int(a.B) + int(b.B)) / 2), 0xff}
+}
+
+type biomeClimate struct {
+	temp, downfall float32
+	noRain         bool
+}
+
+// Values from: http://minecraft.gamepedia.com/Biome
+
+var vanillaBiomeClimates = map[Biome]biomeClimate{
+	BioOcean:                {0.5, 0.5, false},
+	BioPlains:               {0.8, 0.4, false},
+	BioDesert:               {2.0, 0.0, true},
+	BioExtremeHills:         {0.2, 0.3, false},
+	BioForest:               {0.7, 0.8, false},
+	BioTaiga:                {0.25, 0.8, false},
+	BioSwampland:            {0.8, 0.9, false},
+	BioRiver:                {0.5, 0.5, false},
+	BioHell:                 {2.0, 0.0, true},
+	BioSky:                  {0.5, 0.5, true},
+	BioFrozenOcean:          {0.0, 0.5, false},
+	BioFrozenRiver:          {0.0, 0.5, false},
+	BioIcePlains:            {0.0, 0.5, false},
+	BioIceMountains:         {0.0, 0.5, false},
+	BioMushroomIsland:       {0.9, 1.0, false},
+	BioMushroomIslandShore:  {0.9, 1.0, false},
+	BioBeach:                {0.8, 0.4, false},
+	BioDesertHills:          {2.0, 0.0, true},
+	BioForestHills:          {0.7, 0.8, false},
+	BioTaigaHills:           {0.25, 0.8, false},
+	BioExtremeHillsEdge:     {0.2, 0.3, false},
+	BioJungle:               {0.95, 0.9, false},
+	BioJungleHills:          {0.95, 0.9, false},
+	BioJungleEdge:           {0.95, 0.8, false},
+	BioDeepOcean:            {0.5, 0.5, false},
+	BioStoneBeach:           {0.2, 0.3, false},
+	BioColdBeach:            {0.05, 0.3, false},
+	BioBirchForest:          {0.6, 0.6, false},
+	BioBirchForestHills:     {0.6, 0.6, false},
+	BioRoofedForest:         {0.7, 0.8, false},
+	BioColdTaiga:            {-0.5, 0.4, false},
+	BioColdTaigaHills:       {-0.5, 0.4, false},
+	BioMegaTaiga:            {0.3, 0.8, false},
+	BioMegaTaigaHills:       {0.3, 0.8, false},
+	BioExtremeHillsPlus:     {0.2, 0.3, false},
+	BioSavanna:              {1.2, 0.0, true},
+	BioSavannaPlateau:       {1.0, 0.0, true},
+	BioMesa:                 {2.0, 0.0, true},
+	BioMesaPlateauF:         {2.0, 0.0, true},
+	BioMesaPlateau:          {2.0, 0.0, true},
+	BioSmallEndIslands:      {0.5, 0.5, true},
+	BioEndMidlands:          {0.5, 0.5, true},
+	BioEndHighlands:         {0.5, 0.5, true},
+	BioEndBarrens:           {0.5, 0.5, true},
+	BioWarmOcean:            {0.5, 0.5, false},
+	BioLukewarmOcean:        {0.5, 0.5, false},
+	BioColdOcean:            {0.5, 0.5, false},
+	BioDeepWarmOcean:        {0.5, 0.5, false},
+	BioDeepLukewarmOcean:    {0.5, 0.5, false},
+	BioDeepColdOcean:        {0.5, 0.5, false},
+	BioDeepFrozenOcean:      {0.5, 0.5, false},
+	BioTheVoid:              {0.5, 0.5, true},
+	BioSunflowerPlains:      {0.8, 0.4, false},
+	BioDesertM:              {2.0, 0.0, true},
+	BioExtremeHillsM:        {0.2, 0.3, false},
+	BioFlowerForest:         {0.7, 0.8, false},
+	BioTaigaM:               {0.25, 0.8, false},
+	BioSwamplandM:           {0.8, 0.9, false},
+	BioIcePlainsSpikes:      {0.0, 0.5, false},
+	BioIceMountainsSpikes:   {0.0, 0.5, false},
+	BioJungleM:              {0.95, 0.9, false},
+	BioJungleEdgeM:          {0.95, 0.8, false},
+	BioBirchForestM:         {0.7, 0.8, false},
+	BioBirchForestHillsM:    {0.7, 0.8, false},
+	BioRoofedForestM:        {0.7, 0.8, false},
+	BioColdTaigaM:           {-0.5, 0.4, false},
+	BioMegaSpruceTaiga:      {0.25, 0.8, false},
+	BioMegaSpruceTaigaHills: {0.25, 0.8, false},
+	BioExtremeHillsPlusM:    {0.2, 0.3, false},
+	BioSavannaM:             {1.1, 0.0, true},
+	BioSavannaPlateauM:      {1.0, 0.0, true},
+	BioMesaBryce:            {2.0, 0.0, true},
+	BioMesaPlateauFM:        {2.0, 0.0, true},
+	BioMesaPlateauM:         {2.0, 0.0, true},
+	BioBambooJungle:         {0.95, 0.9, false},
+	BioBambooJungleHills:    {0.95, 0.9, false},
+	BioSoulSandValley:       {2.0, 0.0, true},
+	BioCrimsonForest:        {2.0, 0.0, true},
+	BioWarpedForest:         {2.0, 0.0, true},
+	BioBasaltDeltas:         {2.0, 0.0, true},
+	BioDripstoneCaves:       {0.8, 0.4, false},
+	BioLushCaves:            {0.5, 0.5, false},
+}
+
+// Climates of the biomes added in Minecraft 1.18 and later. These biomes have no numeric ID (see BiomeByName).
+var modernBiomeClimates = map[string]biomeClimate{
+	"minecraft:meadow":         {0.5, 0.8, false},
+	"minecraft:grove":          {-0.2, 0.8, false},
+	"minecraft:snowy_slopes":   {-0.3, 0.9, false},
+	"minecraft:frozen_peaks":   {-0.7, 0.9, false},
+	"minecraft:jagged_peaks":   {-0.7, 0.9, false},
+	"minecraft:stony_peaks":    {1.0, 0.3, false},
+	"minecraft:deep_dark":      {0.8, 0.4, false},
+	"minecraft:mangrove_swamp": {0.8, 0.9, false},
+	"minecraft:cherry_grove":   {0.5, 0.8, false},
+}
+
+func (clim biomeClimate) properties(bio Biome) BiomeProperties {
+	props := BiomeProperties{
+		Temperature:   clim.temp,
+		Downfall:      clim.downfall,
+		Precipitation: PrecipRain,
+		GrassColor:    grassColor(clim.temp, clim.downfall),
+		FoliageColor:  foliageColor(clim.temp, clim.downfall),
+		WaterColor:    defaultWaterColor,
+	}
+	switch {
+	case clim.noRain:
+		props.Precipitation = PrecipNone
+	case clim.temp < 0.15:
+		props.Precipitation = PrecipSnow
+		props.Snowy = true
+	}
+
+	switch bio {
+	case BioOcean, BioDeepOcean:
+		props.Ocean = true
+	case BioFrozenOcean, BioDeepFrozenOcean:
+		props.Ocean = true
+		props.WaterColor = rgb(0x3938c9)
+	case BioWarmOcean, BioDeepWarmOcean:
+		props.Ocean = true
+		props.WaterColor = rgb(0x43d5ee)
+	case BioLukewarmOcean, BioDeepLukewarmOcean:
+		props.Ocean = true
+		props.WaterColor = rgb(0x45adf2)
+	case BioColdOcean, BioDeepColdOcean:
+		props.Ocean = true
+		props.WaterColor = rgb(0x3d57d6)
+	case BioFrozenRiver:
+		props.WaterColor = rgb(0x3938c9)
+	case BioHell, BioSoulSandValley, BioCrimsonForest, BioWarpedForest, BioBasaltDeltas:
+		props.Nether = true
+	case BioSwampland, BioSwamplandM:
+		props.GrassColor = rgb(0x6a7039)
+		props.FoliageColor = rgb(0x6a7039)
+		props.WaterColor = rgb(0x617b64)
+	case BioRoofedForest, BioRoofedForestM:
+		props.GrassColor = averageColor(props.GrassColor, rgb(0x28340a))
+	case BioMesa, BioMesaPlateauF, BioMesaPlateau, BioMesaBryce, BioMesaPlateauFM, BioMesaPlateauM:
+		props.GrassColor = rgb(0x90814d)
+		props.FoliageColor = rgb(0x9e814d)
+	}
+
+	return props
+}
+
+func init() {
+	for bio, clim := range vanillaBiomeClimates {
+		RegisterBiomeProperties(bio, clim.properties(bio))
+	}
+}
@@ -1,6 +1,11 @@
 package mcmap
 
-type Biome uint8
+import (
+	"strings"
+	"sync"
+)
+
+type Biome uint16
 
 // Names and values from: http://www.minecraftwiki.net/wiki/Data_values
 
@@ -46,6 +51,18 @@ const (
 	BioMesa                 = 37
 	BioMesaPlateauF         = 38
 	BioMesaPlateau          = 39
+	BioSmallEndIslands      = 40
+	BioEndMidlands          = 41
+	BioEndHighlands         = 42
+	BioEndBarrens           = 43
+	BioWarmOcean            = 44
+	BioLukewarmOcean        = 45
+	BioColdOcean            = 46
+	BioDeepWarmOcean        = 47
+	BioDeepLukewarmOcean    = 48
+	BioDeepColdOcean        = 49
+	BioDeepFrozenOcean      = 50
+	BioTheVoid              = 127
 	BioSunflowerPlains      = 129
 	BioDesertM              = 130
 	BioExtremeHillsM        = 131
@@ -68,6 +85,14 @@ const (
 	BioMesaBryce            = 165
 	BioMesaPlateauFM        = 166
 	BioMesaPlateauM         = 167
+	BioBambooJungle         = 168
+	BioBambooJungleHills    = 169
+	BioSoulSandValley       = 170
+	BioCrimsonForest        = 171
+	BioWarpedForest         = 172
+	BioBasaltDeltas         = 173
+	BioDripstoneCaves       = 174
+	BioLushCaves            = 175
 	BioUncalculated         = 0xff // (-1)
 )
 
@@ -112,6 +137,18 @@ var biomeNames = map[Biome]string{
 	BioMesa:                 "Mesa",
 	BioMesaPlateauF:         "Mesa Plateau F",
 	BioMesaPlateau:          "Mesa Plateau",
+	BioSmallEndIslands:      "Small End Islands",
+	BioEndMidlands:          "End Midlands",
+	BioEndHighlands:         "End Highlands",
+	BioEndBarrens:           "End Barrens",
+	BioWarmOcean:            "Warm Ocean",
+	BioLukewarmOcean:        "Lukewarm Ocean",
+	BioColdOcean:            "Cold Ocean",
+	BioDeepWarmOcean:        "Deep Warm Ocean",
+	BioDeepLukewarmOcean:    "Deep Lukewarm Ocean",
+	BioDeepColdOcean:        "Deep Cold Ocean",
+	BioDeepFrozenOcean:      "Deep Frozen Ocean",
+	BioTheVoid:              "The Void",
 	BioSunflowerPlains:      "Sunflower Plains",
 	BioDesertM:              "Desert M",
 	BioExtremeHillsM:        "Extreme Hills M",
@@ -134,6 +171,14 @@ var biomeNames = map[Biome]string{
 	BioMesaBryce:            "Mesa (Bryce)",
 	BioMesaPlateauFM:        "Mesa Plateau F M",
 	BioMesaPlateauM:         "Mesa Plateau M",
+	BioBambooJungle:         "Bamboo Jungle",
+	BioBambooJungleHills:    "Bamboo Jungle Hills",
+	BioSoulSandValley:       "Soul Sand Valley",
+	BioCrimsonForest:        "Crimson Forest",
+	BioWarpedForest:         "Warped Forest",
+	BioBasaltDeltas:         "Basalt Deltas",
+	BioDripstoneCaves:       "Dripstone Caves",
+	BioLushCaves:            "Lush Caves",
 	BioUncalculated:         "(Uncalculated)",
 }
 
@@ -141,5 +186,233 @@ func (b Biome) String() string {
 	if s, ok := biomeNames[b]; ok {
 		return s
 	}
+	if name := b.Name(); name != "" {
+		return name
+	}
 	return "(Unknown)"
 }
+
+// Namespaced names of the biomes, as used since Minecraft 1.13.
+var biomeIDNames = map[Biome]string{
+	BioOcean:                "minecraft:ocean",
+	BioPlains:               "minecraft:plains",
+	BioDesert:               "minecraft:desert",
+	BioExtremeHills:         "minecraft:mountains",
+	BioForest:               "minecraft:forest",
+	BioTaiga:                "minecraft:taiga",
+	BioSwampland:            "minecraft:swamp",
+	BioRiver:                "minecraft:river",
+	BioHell:                 "minecraft:nether",
+	BioSky:                  "minecraft:the_end",
+	BioFrozenOcean:          "minecraft:frozen_ocean",
+	BioFrozenRiver:          "minecraft:frozen_river",
+	BioIcePlains:            "minecraft:snowy_tundra",
+	BioIceMountains:         "minecraft:snowy_mountains",
+	BioMushroomIsland:       "minecraft:mushroom_fields",
+	BioMushroomIslandShore:  "minecraft:mushroom_field_shore",
+	BioBeach:                "minecraft:beach",
+	BioDesertHills:          "minecraft:desert_hills",
+	BioForestHills:          "minecraft:wooded_hills",
+	BioTaigaHills:           "minecraft:taiga_hills",
+	BioExtremeHillsEdge:     "minecraft:mountain_edge",
+	BioJungle:               "minecraft:jungle",
+	BioJungleHills:          "minecraft:jungle_hills",
+	BioJungleEdge:           "minecraft:jungle_edge",
+	BioDeepOcean:            "minecraft:deep_ocean",
+	BioStoneBeach:           "minecraft:stone_shore",
+	BioColdBeach:            "minecraft:snowy_beach",
+	BioBirchForest:          "minecraft:birch_forest",
+	BioBirchForestHills:     "minecraft:birch_forest_hills",
+	BioRoofedForest:         "minecraft:dark_forest",
+	BioColdTaiga:            "minecraft:snowy_taiga",
+	BioColdTaigaHills:       "minecraft:snowy_taiga_hills",
+	BioMegaTaiga:            "minecraft:giant_tree_taiga",
+	BioMegaTaigaHills:       "minecraft:giant_tree_taiga_hills",
+	BioExtremeHillsPlus:     "minecraft:wooded_mountains",
+	BioSavanna:              "minecraft:savanna",
+	BioSavannaPlateau:       "minecraft:savanna_plateau",
+	BioMesa:                 "minecraft:badlands",
+	BioMesaPlateauF:         "minecraft:wooded_badlands_plateau",
+	BioMesaPlateau:          "minecraft:badlands_plateau",
+	BioSmallEndIslands:      "minecraft:small_end_islands",
+	BioEndMidlands:          "minecraft:end_midlands",
+	BioEndHighlands:         "minecraft:end_highlands",
+	BioEndBarrens:           "minecraft:end_barrens",
+	BioWarmOcean:            "minecraft:warm_ocean",
+	BioLukewarmOcean:        "minecraft:lukewarm_ocean",
+	BioColdOcean:            "minecraft:cold_ocean",
+	BioDeepWarmOcean:        "minecraft:deep_warm_ocean",
+	BioDeepLukewarmOcean:    "minecraft:deep_lukewarm_ocean",
+	BioDeepColdOcean:        "minecraft:deep_cold_ocean",
+	BioDeepFrozenOcean:      "minecraft:deep_frozen_ocean",
+	BioTheVoid:              "minecraft:the_void",
+	BioSunflowerPlains:      "minecraft:sunflower_plains",
+	BioDesertM:              "minecraft:desert_lakes",
+	BioExtremeHillsM:        "minecraft:gravelly_mountains",
+	BioFlowerForest:         "minecraft:flower_forest",
+	BioTaigaM:               "minecraft:taiga_mountains",
+	BioSwamplandM:           "minecraft:swamp_hills",
+	BioIcePlainsSpikes:      "minecraft:ice_spikes",
+	BioJungleM:              "minecraft:modified_jungle",
+	BioJungleEdgeM:          "minecraft:modified_jungle_edge",
+	BioBirchForestM:         "minecraft:tall_birch_forest",
+	BioBirchForestHillsM:    "minecraft:tall_birch_hills",
+	BioRoofedForestM:        "minecraft:dark_forest_hills",
+	BioColdTaigaM:           "minecraft:snowy_taiga_mountains",
+	BioMegaSpruceTaiga:      "minecraft:giant_spruce_taiga",
+	BioMegaSpruceTaigaHills: "minecraft:giant_spruce_taiga_hills",
+	BioExtremeHillsPlusM:    "minecraft:modified_gravelly_mountains",
+	BioSavannaM:             "minecraft:shattered_savanna",
+	BioSavannaPlateauM:      "minecraft:shattered_savanna_plateau",
+	BioMesaBryce:            "minecraft:eroded_badlands",
+	BioMesaPlateauFM:        "minecraft:modified_wooded_badlands_plateau",
+	BioMesaPlateauM:         "minecraft:modified_badlands_plateau",
+	BioBambooJungle:         "minecraft:bamboo_jungle",
+	BioBambooJungleHills:    "minecraft:bamboo_jungle_hills",
+	BioSoulSandValley:       "minecraft:soul_sand_valley",
+	BioCrimsonForest:        "minecraft:crimson_forest",
+	BioWarpedForest:         "minecraft:warped_forest",
+	BioBasaltDeltas:         "minecraft:basalt_deltas",
+	BioDripstoneCaves:       "minecraft:dripstone_caves",
+	BioLushCaves:            "minecraft:lush_caves",
+}
+
+// Biomes renamed in Minecraft 1.16 and 1.18 (old name -> new name).
+var renamedBiomes = map[string]string{
+	"minecraft:nether":                  "minecraft:nether_wastes",
+	"minecraft:mountains":               "minecraft:windswept_hills",
+	"minecraft:snowy_tundra":            "minecraft:snowy_plains",
+	"minecraft:jungle_edge":             "minecraft:sparse_jungle",
+	"minecraft:stone_shore":             "minecraft:stony_shore",
+	"minecraft:giant_tree_taiga":        "minecraft:old_growth_pine_taiga",
+	"minecraft:giant_spruce_taiga":      "minecraft:old_growth_spruce_taiga",
+	"minecraft:wooded_mountains":        "minecraft:windswept_forest",
+	"minecraft:gravelly_mountains":      "minecraft:windswept_gravelly_hills",
+	"minecraft:shattered_savanna":       "minecraft:windswept_savanna",
+	"minecraft:wooded_badlands_plateau": "minecraft:wooded_badlands",
+	"minecraft:tall_birch_forest":       "minecraft:old_growth_birch_forest",
+}
+
+// Biomes removed in Minecraft 1.18 and the biomes replacing them (see name118).
+var removedBiomes118 = map[string]string{
+	"minecraft:badlands_plateau":                 "minecraft:badlands",
+	"minecraft:bamboo_jungle_hills":              "minecraft:bamboo_jungle",
+	"minecraft:birch_forest_hills":               "minecraft:birch_forest",
+	"minecraft:dark_forest_hills":                "minecraft:dark_forest",
+	"minecraft:deep_warm_ocean":                  "minecraft:warm_ocean",
+	"minecraft:desert_hills":                     "minecraft:desert",
+	"minecraft:desert_lakes":                     "minecraft:desert",
+	"minecraft:giant_spruce_taiga_hills":         "minecraft:old_growth_spruce_taiga",
+	"minecraft:giant_tree_taiga_hills":           "minecraft:old_growth_pine_taiga",
+	"minecraft:jungle_hills":                     "minecraft:jungle",
+	"minecraft:modified_badlands_plateau":        "minecraft:badlands",
+	"minecraft:modified_gravelly_mountains":      "minecraft:windswept_gravelly_hills",
+	"minecraft:modified_jungle":                  "minecraft:jungle",
+	"minecraft:modified_jungle_edge":             "minecraft:sparse_jungle",
+	"minecraft:modified_wooded_badlands_plateau": "minecraft:wooded_badlands",
+	"minecraft:mountain_edge":                    "minecraft:windswept_hills",
+	"minecraft:mushroom_field_shore":             "minecraft:mushroom_fields",
+	"minecraft:shattered_savanna_plateau":        "minecraft:windswept_savanna",
+	"minecraft:snowy_mountains":                  "minecraft:snowy_plains",
+	"minecraft:snowy_taiga_hills":                "minecraft:snowy_taiga",
+	"minecraft:snowy_taiga_mountains":            "minecraft:snowy_taiga",
+	"minecraft:swamp_hills":                      "minecraft:swamp",
+	"minecraft:taiga_hills":                      "minecraft:taiga",
+	"minecraft:taiga_mountains":                  "minecraft:taiga",
+	"minecraft:tall_birch_hills":                 "minecraft:old_growth_birch_forest",
+	"minecraft:wooded_hills":                     "minecraft:forest",
+}
+
+var (
+	biomesByNameOnce sync.Once
+	biomesByName     map[string]Biome
+
+	dynamicBiomesLock sync.RWMutex
+	dynamicBiomeNames = make(map[Biome]string)
+	dynamicBiomeIDs   = make(map[string]Biome)
+	nextDynamicBiome  = Biome(firstDynamicBiome)
+)
+
+// firstDynamicBiome is the first ID given to biomes without a numeric ID (added in Minecraft 1.18 or by mods).
+// Lower IDs are numeric biome IDs, as stored before Minecraft 1.18.
+const (
+	firstDynamicBiome = 0x1000
+	maxDynamicBiome   = 0xffff
+)
+
+func initBiomesByName() {
+	biomesByName = make(map[string]Biome)
+	for bio, name := range biomeIDNames {
+		biomesByName[name] = bio
+		if newName, ok := renamedBiomes[name]; ok {
+			biomesByName[newName] = bio
+		}
+	}
+}
+
+// Name returns the namespaced name of the biome (e.g. "minecraft:plains"), as used since Minecraft 1.13.
+// An empty string is returned, if the biome has no name.
+func (b Biome) Name() string {
+	if name, ok := biomeIDNames[b]; ok {
+		return name
+	}
+
+	dynamicBiomesLock.RLock()
+	defer dynamicBiomesLock.RUnlock()
+	return dynamicBiomeNames[b]
+}
+
+// name118 returns the name of the biome, as used since Minecraft 1.18. Removed biomes are replaced like the game does.
+func (b Biome) name118() string {
+	name := b.Name()
+	if newName, ok := renamedBiomes[name]; ok {
+		return newName
+	}
+	if newName, ok := removedBiomes118[name]; ok {
+		return newName
+	}
+	return name
+}
+
+// numericBiome returns the biome, if it has a numeric ID that can be stored in chunks before Minecraft 1.18.
+// Otherwise def is returned.
+func (b Biome) numericBiome(def Biome) Biome {
+	if b >= firstDynamicBiome {
+		return def
+	}
+	return b
+}
+
+// BiomeByName returns the biome with the given namespaced name. Old and new names (see Minecraft 1.18) are accepted.
+//
+// Biomes without a numeric ID (biomes added in Minecraft 1.18 or by mods) get a dynamically allocated ID,
+// that is only valid while the program runs. If no more IDs are available, BioUncalculated is returned.
+func BiomeByName(name string) Biome {
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+
+	biomesByNameOnce.Do(initBiomesByName)
+	if bio, ok := biomesByName[name]; ok {
+		return bio
+	}
+
+	dynamicBiomesLock.Lock()
+	defer dynamicBiomesLock.Unlock()
+
+	if bio, ok := dynamicBiomeIDs[name]; ok {
+		return bio
+	}
+	if nextDynamicBiome == maxDynamicBiome {
+		return BioUncalculated
+	}
+
+	bio := nextDynamicBiome
+	nextDynamicBiome++
+	dynamicBiomeNames[bio] = name
+	dynamicBiomeIDs[name] = bio
+	if clim, ok := modernBiomeClimates[name]; ok {
+		RegisterBiomeProperties(bio, clim.properties(bio))
+	}
+	return bio
+}
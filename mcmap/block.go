@@ -10,20 +10,38 @@ type Block struct {
 	ID                   BlockID
 	Data                 byte            // Actually only a half-byte.
 	BlockLight, SkyLight byte            // Also, only half-bytes.
-	TileEntity           nbt.TagCompound // The x, y and z values in here can be ignored, will automatically be fixed on saving. Will be nil, if no TileEntity is available.
+	TileEntity           nbt.TagCompound // The x, y and z values in here can be ignored, will automatically be fixed on saving. Will be nil, if no TileEntity is available. See AsContainer, AsSign, ... for typed access.
 	Tick                 *TileTick       // If nil, no TileTick info is available for this block
+	State                *BlockState     // Block state of chunks in the Minecraft 1.13+ format. Only used, if it matches ID and Data, see SetState.
 }
 
+// TileTick is a scheduled update of a block or fluid (see Chunk.ScheduledTicks).
+// I is the numeric block ID, T the delay in ticks and P the priority (Minecraft 1.8+, lower values are updated first).
 type TileTick struct {
 	i, t, p int32
 	hasP    bool
+	name    string // Since Minecraft 1.8, the block is stored by name.
 }
 
+// NewTileTick creates a scheduled update of the block (or fluid) with the given name. The numeric ID is looked up in
+// the block registry; since Minecraft 1.13 the name must be the name of the block state, e.g. "minecraft:repeater".
+func NewTileTick(name string, blocks *BlockRegistry, delay, priority int32) *TileTick {
+	id, _ := blocks.ID(name)
+	return &TileTick{i: int32(id), t: delay, p: priority, hasP: true, name: NormalizeBlockName(name)}
+}
+
+// Name returns the name of the updated block (empty for ticks from Minecraft versions before 1.8).
+func (tt *TileTick) Name() string { return tt.name }
+
 func (tt *TileTick) I() int32 { return tt.i }
 func (tt *TileTick) T() int32 { return tt.t }
 func (tt *TileTick) P() int32 { return tt.p }
 
-func (tt *TileTick) SetI(i int32) { tt.i = i }
+func (tt *TileTick) SetI(i int32) {
+	tt.i = i
+	tt.name = ""
+}
+
 func (tt *TileTick) SetT(t int32) { tt.t = t }
 
 func (tt *TileTick) SetP(p int32) {
@@ -197,6 +215,7 @@ const (
 	BlkDropper                    = 158
 	BlkStainedClay                = 159
 	BlkStainedGlassPane           = 160
+	BlkLeaves2                    = 161
 	BlkWood2                      = 162
 	BlkAcaciaWoodStairs           = 163
 	BlkDarkOakWoodStairs          = 164
@@ -211,6 +230,84 @@ const (
 	BlkIronTrapdoor               = 167
 	BlkPrismarine                 = 168
 	BlkSeaLantern                 = 169
+	BlkStandingBanner             = 176
+	BlkWallBanner                 = 177
+	BlkInvertedDaylightSensor     = 178
+	BlkRedSandstone               = 179
+	BlkRedSandstoneStairs         = 180
+	BlkDoubleRedSandstoneSlab     = 181
+	BlkRedSandstoneSlab           = 182
+	BlkSpruceFenceGate            = 183
+	BlkBirchFenceGate             = 184
+	BlkJungleFenceGate            = 185
+	BlkDarkOakFenceGate           = 186
+	BlkAcaciaFenceGate            = 187
+	BlkSpruceFence                = 188
+	BlkBirchFence                 = 189
+	BlkJungleFence                = 190
+	BlkDarkOakFence               = 191
+	BlkAcaciaFence                = 192
+	BlkSpruceDoor                 = 193
+	BlkBirchDoor                  = 194
+	BlkJungleDoor                 = 195
+	BlkAcaciaDoor                 = 196
+	BlkDarkOakDoor                = 197
+	BlkEndRod                     = 198
+	BlkChorusPlant                = 199
+	BlkChorusFlower               = 200
+	BlkPurpurBlock                = 201
+	BlkPurpurPillar               = 202
+	BlkPurpurStairs               = 203
+	BlkPurpurDoubleSlab           = 204
+	BlkPurpurSlab                 = 205
+	BlkEndStoneBricks             = 206
+	BlkBeetroots                  = 207
+	BlkGrassPath                  = 208
+	BlkEndGateway                 = 209
+	BlkRepeatingCommandBlock      = 210
+	BlkChainCommandBlock          = 211
+	BlkFrostedIce                 = 212
+	BlkMagmaBlock                 = 213
+	BlkNetherWartBlock            = 214
+	BlkRedNetherBrick             = 215
+	BlkBoneBlock                  = 216
+	BlkStructureVoid              = 217
+	BlkObserver                   = 218
+	BlkWhiteShulkerBox            = 219
+	BlkOrangeShulkerBox           = 220
+	BlkMagentaShulkerBox          = 221
+	BlkLightBlueShulkerBox        = 222
+	BlkYellowShulkerBox           = 223
+	BlkLimeShulkerBox             = 224
+	BlkPinkShulkerBox             = 225
+	BlkGrayShulkerBox             = 226
+	BlkLightGrayShulkerBox        = 227
+	BlkCyanShulkerBox             = 228
+	BlkPurpleShulkerBox           = 229
+	BlkBlueShulkerBox             = 230
+	BlkBrownShulkerBox            = 231
+	BlkGreenShulkerBox            = 232
+	BlkRedShulkerBox              = 233
+	BlkBlackShulkerBox            = 234
+	BlkWhiteGlazedTerracotta      = 235
+	BlkOrangeGlazedTerracotta     = 236
+	BlkMagentaGlazedTerracotta    = 237
+	BlkLightBlueGlazedTerracotta  = 238
+	BlkYellowGlazedTerracotta     = 239
+	BlkLimeGlazedTerracotta       = 240
+	BlkPinkGlazedTerracotta       = 241
+	BlkGrayGlazedTerracotta       = 242
+	BlkLightGrayGlazedTerracotta  = 243
+	BlkCyanGlazedTerracotta       = 244
+	BlkPurpleGlazedTerracotta     = 245
+	BlkBlueGlazedTerracotta       = 246
+	BlkBrownGlazedTerracotta      = 247
+	BlkGreenGlazedTerracotta      = 248
+	BlkRedGlazedTerracotta        = 249
+	BlkBlackGlazedTerracotta      = 250
+	BlkConcrete                   = 251
+	BlkConcretePowder             = 252
+	BlkStructureBlock             = 255
 
 	// Aliases
 	BlkRose      = BlkFlower
@@ -386,6 +483,7 @@ var blockNames = map[BlockID]string{
 	BlkPackedIce:                  "Packed Ice",
 	BlkLargeFlower:                "Large Flower",
 	BlkStainedGlassPane:           "Stained Glass Pane",
+	BlkLeaves2:                    "Leaves 2",
 	BlkWood2:                      "Wood 2",
 	BlkAcaciaWoodStairs:           "Acacia Wood Stairs",
 	BlkDarkOakWoodStairs:          "Dark Oak Wood Stairs",
@@ -394,12 +492,93 @@ var blockNames = map[BlockID]string{
 	BlkIronTrapdoor:               "Iron Trapdoor",
 	BlkPrismarine:                 "Prismarine",
 	BlkSeaLantern:                 "Sea Lantern",
+	BlkStandingBanner:             "Standing Banner",
+	BlkWallBanner:                 "Wall Banner",
+	BlkInvertedDaylightSensor:     "Inverted Daylight Sensor",
+	BlkRedSandstone:               "Red Sandstone",
+	BlkRedSandstoneStairs:         "Red Sandstone Stairs",
+	BlkDoubleRedSandstoneSlab:     "Double Red Sandstone Slab",
+	BlkRedSandstoneSlab:           "Red Sandstone Slab",
+	BlkSpruceFenceGate:            "Spruce Fence Gate",
+	BlkBirchFenceGate:             "Birch Fence Gate",
+	BlkJungleFenceGate:            "Jungle Fence Gate",
+	BlkDarkOakFenceGate:           "Dark Oak Fence Gate",
+	BlkAcaciaFenceGate:            "Acacia Fence Gate",
+	BlkSpruceFence:                "Spruce Fence",
+	BlkBirchFence:                 "Birch Fence",
+	BlkJungleFence:                "Jungle Fence",
+	BlkDarkOakFence:               "Dark Oak Fence",
+	BlkAcaciaFence:                "Acacia Fence",
+	BlkSpruceDoor:                 "Spruce Door",
+	BlkBirchDoor:                  "Birch Door",
+	BlkJungleDoor:                 "Jungle Door",
+	BlkAcaciaDoor:                 "Acacia Door",
+	BlkDarkOakDoor:                "Dark Oak Door",
+	BlkEndRod:                     "End Rod",
+	BlkChorusPlant:                "Chorus Plant",
+	BlkChorusFlower:               "Chorus Flower",
+	BlkPurpurBlock:                "Purpur Block",
+	BlkPurpurPillar:               "Purpur Pillar",
+	BlkPurpurStairs:               "Purpur Stairs",
+	BlkPurpurDoubleSlab:           "Purpur Double Slab",
+	BlkPurpurSlab:                 "Purpur Slab",
+	BlkEndStoneBricks:             "End Stone Bricks",
+	BlkBeetroots:                  "Beetroots",
+	BlkGrassPath:                  "Grass Path",
+	BlkEndGateway:                 "End Gateway",
+	BlkRepeatingCommandBlock:      "Repeating Command Block",
+	BlkChainCommandBlock:          "Chain Command Block",
+	BlkFrostedIce:                 "Frosted Ice",
+	BlkMagmaBlock:                 "Magma Block",
+	BlkNetherWartBlock:            "Nether Wart Block",
+	BlkRedNetherBrick:             "Red Nether Brick",
+	BlkBoneBlock:                  "Bone Block",
+	BlkStructureVoid:              "Structure Void",
+	BlkObserver:                   "Observer",
+	BlkWhiteShulkerBox:            "White Shulker Box",
+	BlkOrangeShulkerBox:           "Orange Shulker Box",
+	BlkMagentaShulkerBox:          "Magenta Shulker Box",
+	BlkLightBlueShulkerBox:        "Light Blue Shulker Box",
+	BlkYellowShulkerBox:           "Yellow Shulker Box",
+	BlkLimeShulkerBox:             "Lime Shulker Box",
+	BlkPinkShulkerBox:             "Pink Shulker Box",
+	BlkGrayShulkerBox:             "Gray Shulker Box",
+	BlkLightGrayShulkerBox:        "Light Gray Shulker Box",
+	BlkCyanShulkerBox:             "Cyan Shulker Box",
+	BlkPurpleShulkerBox:           "Purple Shulker Box",
+	BlkBlueShulkerBox:             "Blue Shulker Box",
+	BlkBrownShulkerBox:            "Brown Shulker Box",
+	BlkGreenShulkerBox:            "Green Shulker Box",
+	BlkRedShulkerBox:              "Red Shulker Box",
+	BlkBlackShulkerBox:            "Black Shulker Box",
+	BlkWhiteGlazedTerracotta:      "White Glazed Terracotta",
+	BlkOrangeGlazedTerracotta:     "Orange Glazed Terracotta",
+	BlkMagentaGlazedTerracotta:    "Magenta Glazed Terracotta",
+	BlkLightBlueGlazedTerracotta:  "Light Blue Glazed Terracotta",
+	BlkYellowGlazedTerracotta:     "Yellow Glazed Terracotta",
+	BlkLimeGlazedTerracotta:       "Lime Glazed Terracotta",
+	BlkPinkGlazedTerracotta:       "Pink Glazed Terracotta",
+	BlkGrayGlazedTerracotta:       "Gray Glazed Terracotta",
+	BlkLightGrayGlazedTerracotta:  "Light Gray Glazed Terracotta",
+	BlkCyanGlazedTerracotta:       "Cyan Glazed Terracotta",
+	BlkPurpleGlazedTerracotta:     "Purple Glazed Terracotta",
+	BlkBlueGlazedTerracotta:       "Blue Glazed Terracotta",
+	BlkBrownGlazedTerracotta:      "Brown Glazed Terracotta",
+	BlkGreenGlazedTerracotta:      "Green Glazed Terracotta",
+	BlkRedGlazedTerracotta:        "Red Glazed Terracotta",
+	BlkBlackGlazedTerracotta:      "Black Glazed Terracotta",
+	BlkConcrete:                   "Concrete",
+	BlkConcretePowder:             "Concrete Powder",
+	BlkStructureBlock:             "Structure Block",
 }
 
 func (b BlockID) String() string {
 	if s, ok := blockNames[b]; ok {
 		return s
 	}
+	if name, ok := DefaultBlocks.Name(b); ok {
+		return name
+	}
 
 	return "(unused)"
 }
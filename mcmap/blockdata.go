@@ -0,0 +1,528 @@
+package mcmap
+
+// Facing is the direction a block is facing.
+type Facing byte
+
+// Valid values for Facing
+const (
+	FacingDown Facing = iota
+	FacingUp
+	FacingNorth
+	FacingSouth
+	FacingWest
+	FacingEast
+)
+
+var facingNames = map[Facing]string{
+	FacingDown:  "down",
+	FacingUp:    "up",
+	FacingNorth: "north",
+	FacingSouth: "south",
+	FacingWest:  "west",
+	FacingEast:  "east",
+}
+
+func (f Facing) String() string {
+	if s, ok := facingNames[f]; ok {
+		return s
+	}
+	return "(unknown)"
+}
+
+// Opposite returns the opposite direction.
+func (f Facing) Opposite() Facing { return f ^ 1 }
+
+// Color is the color of dyed blocks (wool, stained glass, ...).
+type Color byte
+
+// Valid values for Color
+const (
+	ColorWhite Color = iota
+	ColorOrange
+	ColorMagenta
+	ColorLightBlue
+	ColorYellow
+	ColorLime
+	ColorPink
+	ColorGray
+	ColorLightGray
+	ColorCyan
+	ColorPurple
+	ColorBlue
+	ColorBrown
+	ColorGreen
+	ColorRed
+	ColorBlack
+)
+
+var colorNames = [...]string{"white", "orange", "magenta", "light_blue", "yellow", "lime", "pink", "gray", "light_gray", "cyan", "purple", "blue", "brown", "green", "red", "black"}
+
+func (c Color) String() string {
+	if int(c) < len(colorNames) {
+		return colorNames[c]
+	}
+	return "(unknown)"
+}
+
+// WoodType is the type of wood of wooden blocks.
+type WoodType byte
+
+// Valid values for WoodType
+const (
+	WoodOak WoodType = iota
+	WoodSpruce
+	WoodBirch
+	WoodJungle
+	WoodAcacia
+	WoodDarkOak
+)
+
+var woodTypeNames = [...]string{"oak", "spruce", "birch", "jungle", "acacia", "dark_oak"}
+
+func (w WoodType) String() string {
+	if int(w) < len(woodTypeNames) {
+		return woodTypeNames[w]
+	}
+	return "(unknown)"
+}
+
+// Axis is the orientation of blocks like logs or pillars.
+type Axis byte
+
+// Valid values for Axis
+const (
+	AxisY Axis = iota
+	AxisX
+	AxisZ
+	AxisNone // Logs with bark on all sides.
+)
+
+var axisNames = [...]string{"y", "x", "z", "none"}
+
+func (a Axis) String() string {
+	if int(a) < len(axisNames) {
+		return axisNames[a]
+	}
+	return "(unknown)"
+}
+
+// Half tells, whether a block occupies the bottom or top half of a block space (slabs, stairs, trapdoors),
+// or whether it is the lower or upper part of a two block high block (doors, large flowers).
+type Half byte
+
+// Valid values for Half
+const (
+	HalfBottom Half = iota
+	HalfTop
+)
+
+func (h Half) String() string {
+	if h == HalfTop {
+		return "top"
+	}
+	return "bottom"
+}
+
+// Hinge is the side of the hinge of a door.
+type Hinge byte
+
+// Valid values for Hinge
+const (
+	HingeLeft Hinge = iota
+	HingeRight
+)
+
+func (h Hinge) String() string {
+	if h == HingeRight {
+		return "right"
+	}
+	return "left"
+}
+
+// facingInvalid marks data values without a valid facing in the encodings below.
+const facingInvalid Facing = 0xff
+
+// Facing encodings as used in the data values. The data value (masked) is the index.
+var (
+	facingsStandard   = []Facing{FacingDown, FacingUp, FacingNorth, FacingSouth, FacingWest, FacingEast}
+	facingsHorizontal = []Facing{FacingSouth, FacingWest, FacingNorth, FacingEast}
+	facingsStairs     = []Facing{FacingEast, FacingWest, FacingSouth, FacingNorth}
+	facingsDoor       = []Facing{FacingEast, FacingSouth, FacingWest, FacingNorth}
+	facingsTrapdoor   = []Facing{FacingNorth, FacingSouth, FacingWest, FacingEast}
+	facingsTorch      = []Facing{facingInvalid, FacingEast, FacingWest, FacingSouth, FacingNorth, FacingUp}
+	facingsButton     = []Facing{FacingDown, FacingEast, FacingWest, FacingSouth, FacingNorth, FacingUp}
+	facingsLever      = []Facing{FacingDown, FacingEast, FacingWest, FacingSouth, FacingNorth, FacingUp, FacingUp, FacingDown}
+)
+
+type facingEncoding struct {
+	mask    byte
+	facings []Facing
+}
+
+var blockFacings = make(map[BlockID]facingEncoding)
+
+func addFacings(enc facingEncoding, ids ...BlockID) {
+	for _, id := range ids {
+		blockFacings[id] = enc
+	}
+}
+
+var (
+	stairIDs     = []BlockID{BlkOakWoodStairs, BlkCobblestoneStairs, BlkBrickStairs, BlkStoneBrickStairs, BlkNetherBrickStairs, BlkSandstoneStairs, BlkSpruceWoodStairs, BlkBirchWoodStairs, BlkJungleWoodStairs, BlkQuartzStairs, BlkAcaciaWoodStairs, BlkDarkOakWoodStairs, BlkRedSandstoneStairs, BlkPurpurStairs}
+	doorIDs      = []BlockID{BlkWoodenDoor, BlkIronDoor, BlkSpruceDoor, BlkBirchDoor, BlkJungleDoor, BlkAcaciaDoor, BlkDarkOakDoor}
+	trapdoorIDs  = []BlockID{BlkTrapdoor, BlkIronTrapdoor}
+	fenceGateIDs = []BlockID{BlkFenceGate, BlkSpruceFenceGate, BlkBirchFenceGate, BlkJungleFenceGate, BlkDarkOakFenceGate, BlkAcaciaFenceGate}
+	slabIDs      = []BlockID{BlkSlabs, BlkWoodenSlab, BlkRedSandstoneSlab, BlkPurpurSlab}
+)
+
+func init() {
+	addFacings(facingEncoding{7, facingsStandard},
+		BlkDispenser, BlkDropper, BlkPiston, BlkStickyPiston, BlkPistonExtension, BlkObserver, BlkEndRod, BlkHopper,
+		BlkCommandBlock, BlkRepeatingCommandBlock, BlkChainCommandBlock, BlkChest, BlkTrappedChest, BlkEnderChest,
+		BlkFurnace, BlkBurningFurnace, BlkLadders, BlkWallSign, BlkWallBanner, BlkMobHead)
+	for id := BlockID(BlkWhiteShulkerBox); id <= BlkBlackShulkerBox; id++ {
+		addFacings(facingEncoding{7, facingsStandard}, id)
+	}
+	addFacings(facingEncoding{3, facingsHorizontal},
+		BlkPumpkin, BlkJackOLantern, BlkBed, BlkCocoa, BlkAnvil, BlkTripwireHook, BlkEndPortalBlock,
+		BlkRedstoneRepeaterInactive, BlkRedstoneRepeaterActive, BlkRedstoneComparatorInactive, BlkRedstoneComparatorActive)
+	addFacings(facingEncoding{3, facingsHorizontal}, fenceGateIDs...)
+	for id := BlockID(BlkWhiteGlazedTerracotta); id <= BlkBlackGlazedTerracotta; id++ {
+		addFacings(facingEncoding{3, facingsHorizontal}, id)
+	}
+	addFacings(facingEncoding{3, facingsStairs}, stairIDs...)
+	addFacings(facingEncoding{3, facingsDoor}, doorIDs...)
+	addFacings(facingEncoding{3, facingsTrapdoor}, trapdoorIDs...)
+	addFacings(facingEncoding{7, facingsTorch}, BlkTorch, BlkRedstoneTorchInactive, BlkRedstoneTorchActive)
+	addFacings(facingEncoding{7, facingsButton}, BlkStoneButton, BlkWoodenButton)
+	addFacings(facingEncoding{7, facingsLever}, BlkLever)
+}
+
+func isOneOf(id BlockID, ids []BlockID) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isUpperDoor checks, if the block is the upper half of a door. The upper half of a door stores the hinge instead of the facing.
+func (b Block) isUpperDoor() bool {
+	return isOneOf(b.ID, doorIDs) && (b.Data&8 != 0)
+}
+
+// Facing returns the direction the block is facing. ok is false, if the block has no facing information in its data value.
+func (b Block) Facing() (f Facing, ok bool) {
+	enc, ok := blockFacings[b.ID]
+	if !ok || b.isUpperDoor() {
+		return 0, false
+	}
+
+	i := int(b.Data & enc.mask)
+	if i >= len(enc.facings) || enc.facings[i] == facingInvalid {
+		return 0, false
+	}
+	return enc.facings[i], true
+}
+
+// SetFacing sets the facing of the block. It returns false, if the block has no facing information or can not face in that direction.
+func (b *Block) SetFacing(f Facing) bool {
+	enc, ok := blockFacings[b.ID]
+	if !ok || b.isUpperDoor() {
+		return false
+	}
+
+	for i, ef := range enc.facings {
+		if ef == f {
+			b.Data = (b.Data &^ enc.mask) | byte(i)
+			return true
+		}
+	}
+	return false
+}
+
+var coloredBlocks = []BlockID{BlkWool, BlkStainedGlass, BlkStainedClay, BlkStainedGlassPane, BlkCarpet, BlkConcrete, BlkConcretePowder}
+
+// Color returns the color of dyed blocks. For shulker boxes and glazed terracotta, the color is determined by the ID.
+func (b Block) Color() (c Color, ok bool) {
+	switch {
+	case isOneOf(b.ID, coloredBlocks):
+		return Color(b.Data & 0xf), true
+	case b.ID >= BlkWhiteShulkerBox && b.ID <= BlkBlackShulkerBox:
+		return Color(b.ID - BlkWhiteShulkerBox), true
+	case b.ID >= BlkWhiteGlazedTerracotta && b.ID <= BlkBlackGlazedTerracotta:
+		return Color(b.ID - BlkWhiteGlazedTerracotta), true
+	}
+	return 0, false
+}
+
+// SetColor sets the color of dyed blocks. This might change the ID (shulker boxes, glazed terracotta).
+// It returns false, if the block can not be dyed.
+func (b *Block) SetColor(c Color) bool {
+	c &= 0xf
+	switch {
+	case isOneOf(b.ID, coloredBlocks):
+		b.Data = byte(c)
+	case b.ID >= BlkWhiteShulkerBox && b.ID <= BlkBlackShulkerBox:
+		b.ID = BlkWhiteShulkerBox + BlockID(c)
+	case b.ID >= BlkWhiteGlazedTerracotta && b.ID <= BlkBlackGlazedTerracotta:
+		b.ID = BlkWhiteGlazedTerracotta + BlockID(c)
+	default:
+		return false
+	}
+	return true
+}
+
+// Wooden blocks, where the wood type is determined by the ID. Indexed by WoodType.
+var woodIDFamilies = [][]BlockID{
+	{BlkOakWoodStairs, BlkSpruceWoodStairs, BlkBirchWoodStairs, BlkJungleWoodStairs, BlkAcaciaWoodStairs, BlkDarkOakWoodStairs},
+	{BlkFence, BlkSpruceFence, BlkBirchFence, BlkJungleFence, BlkAcaciaFence, BlkDarkOakFence},
+	{BlkFenceGate, BlkSpruceFenceGate, BlkBirchFenceGate, BlkJungleFenceGate, BlkAcaciaFenceGate, BlkDarkOakFenceGate},
+	{BlkWoodenDoor, BlkSpruceDoor, BlkBirchDoor, BlkJungleDoor, BlkAcaciaDoor, BlkDarkOakDoor},
+}
+
+// WoodType returns the type of wood of wooden blocks (planks, logs, leaves, saplings, wooden slabs, stairs, fences, fence gates and doors).
+func (b Block) WoodType() (w WoodType, ok bool) {
+	switch b.ID {
+	case BlkWoodPlanks, BlkWoodenSlab, BlkWoodenDoubleSlab, BlkSaplings:
+		w = WoodType(b.Data & 7)
+		return w, w <= WoodDarkOak
+	case BlkWood, BlkLeaves:
+		return WoodType(b.Data & 3), true
+	case BlkWood2, BlkLeaves2:
+		w = WoodAcacia + WoodType(b.Data&3)
+		return w, w <= WoodDarkOak
+	}
+
+	for _, family := range woodIDFamilies {
+		for i, id := range family {
+			if id == b.ID {
+				return WoodType(i), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// SetWoodType sets the type of wood. This might change the ID (logs, leaves, stairs, ...).
+// It returns false, if the block is not a wooden block.
+func (b *Block) SetWoodType(w WoodType) bool {
+	if w > WoodDarkOak {
+		return false
+	}
+
+	switch b.ID {
+	case BlkWoodPlanks, BlkWoodenSlab, BlkWoodenDoubleSlab, BlkSaplings:
+		b.Data = (b.Data &^ 7) | byte(w)
+		return true
+	case BlkWood, BlkWood2, BlkLeaves, BlkLeaves2:
+		isLeaves := (b.ID == BlkLeaves) || (b.ID == BlkLeaves2)
+		if w >= WoodAcacia {
+			b.ID = BlkWood2
+			if isLeaves {
+				b.ID = BlkLeaves2
+			}
+			w -= WoodAcacia
+		} else {
+			b.ID = BlkWood
+			if isLeaves {
+				b.ID = BlkLeaves
+			}
+		}
+		b.Data = (b.Data &^ 3) | byte(w)
+		return true
+	}
+
+	for _, family := range woodIDFamilies {
+		if isOneOf(b.ID, family) {
+			b.ID = family[w]
+			return true
+		}
+	}
+	return false
+}
+
+// Axis returns the orientation of logs and pillars (quartz, purpur, hay bales, bone blocks).
+func (b Block) Axis() (a Axis, ok bool) {
+	switch b.ID {
+	case BlkWood, BlkWood2:
+		return Axis((b.Data >> 2) & 3), true
+	case BlkHayBlock, BlkBoneBlock, BlkPurpurPillar:
+		a = Axis((b.Data >> 2) & 3)
+		return a, a != AxisNone
+	case BlkBlockOfQuartz:
+		if b.Data >= 2 && b.Data <= 4 {
+			return []Axis{AxisY, AxisX, AxisZ}[b.Data-2], true
+		}
+	}
+	return 0, false
+}
+
+// SetAxis sets the orientation of logs and pillars. It returns false, if the block has no orientation.
+// Quartz blocks will be turned into pillar quartz blocks.
+func (b *Block) SetAxis(a Axis) bool {
+	switch b.ID {
+	case BlkWood, BlkWood2:
+		if a > AxisNone {
+			return false
+		}
+	case BlkHayBlock, BlkBoneBlock, BlkPurpurPillar:
+		if a >= AxisNone {
+			return false
+		}
+	case BlkBlockOfQuartz:
+		switch a {
+		case AxisY:
+			b.Data = 2
+		case AxisX:
+			b.Data = 3
+		case AxisZ:
+			b.Data = 4
+		default:
+			return false
+		}
+		return true
+	default:
+		return false
+	}
+
+	b.Data = (b.Data &^ 0xc) | (byte(a) << 2)
+	return true
+}
+
+// halfBit returns the bit of the data value storing the half of a block.
+func halfBit(id BlockID) byte {
+	switch {
+	case isOneOf(id, stairIDs):
+		return 4
+	case isOneOf(id, slabIDs), isOneOf(id, trapdoorIDs), isOneOf(id, doorIDs), id == BlkLargeFlower:
+		return 8
+	}
+	return 0
+}
+
+// Half returns, whether slabs, stairs (upside down) and trapdoors are in the top half of a block space,
+// or whether doors and large flowers are the upper block.
+func (b Block) Half() (h Half, ok bool) {
+	bit := halfBit(b.ID)
+	if bit == 0 {
+		return 0, false
+	}
+	if b.Data&bit != 0 {
+		return HalfTop, true
+	}
+	return HalfBottom, true
+}
+
+// SetHalf sets the half of a block (see Half). It returns false, if the block has no half.
+//
+// Note that doors store different information in the upper and lower block, so you should use NewDoor to create doors.
+func (b *Block) SetHalf(h Half) bool {
+	bit := halfBit(b.ID)
+	if bit == 0 {
+		return false
+	}
+	if h == HalfTop {
+		b.Data |= bit
+	} else {
+		b.Data &^= bit
+	}
+	return true
+}
+
+// Hinge returns the side of the hinge of a door. Only available in the upper block of a door.
+func (b Block) Hinge() (h Hinge, ok bool) {
+	if !b.isUpperDoor() {
+		return 0, false
+	}
+	return Hinge(b.Data & 1), true
+}
+
+// SetHinge sets the side of the hinge of a door. It returns false, if the block is not the upper block of a door.
+func (b *Block) SetHinge(h Hinge) bool {
+	if !b.isUpperDoor() {
+		return false
+	}
+	b.Data = (b.Data &^ 1) | byte(h&1)
+	return true
+}
+
+// Open returns, whether doors (lower block), trapdoors and fence gates are open.
+func (b Block) Open() (open, ok bool) {
+	switch {
+	case isOneOf(b.ID, doorIDs) && !b.isUpperDoor(), isOneOf(b.ID, trapdoorIDs), isOneOf(b.ID, fenceGateIDs):
+		return b.Data&4 != 0, true
+	}
+	return false, false
+}
+
+// SetOpen opens or closes doors (lower block), trapdoors and fence gates. It returns false for other blocks.
+func (b *Block) SetOpen(open bool) bool {
+	if _, ok := b.Open(); !ok {
+		return false
+	}
+	if open {
+		b.Data |= 4
+	} else {
+		b.Data &^= 4
+	}
+	return true
+}
+
+// NewFacingBlock creates a block of the given ID facing in direction f. ok is false, if the block can not face in that direction.
+func NewFacingBlock(id BlockID, f Facing) (blk Block, ok bool) {
+	blk.ID = id
+	ok = blk.SetFacing(f)
+	return
+}
+
+// NewColoredBlock creates a dyed block (wool, stained glass, carpet, ...). ok is false, if the block can not be dyed.
+func NewColoredBlock(id BlockID, c Color) (blk Block, ok bool) {
+	blk.ID = id
+	ok = blk.SetColor(c)
+	return
+}
+
+// NewWoodenBlock creates a wooden block (planks, saplings, leaves, wooden stairs, ...) of the given wood type.
+// Pass any block of the family as id (e.g. BlkOakWoodStairs for stairs). ok is false, if the block is not wooden.
+func NewWoodenBlock(id BlockID, w WoodType) (blk Block, ok bool) {
+	blk.ID = id
+	ok = blk.SetWoodType(w)
+	return
+}
+
+// NewLog creates a log of the given wood type and orientation.
+func NewLog(w WoodType, a Axis) Block {
+	blk, _ := NewWoodenBlock(BlkWood, w)
+	blk.SetAxis(a)
+	return blk
+}
+
+// NewStairs creates stairs of the given ID, facing in direction f. If half is HalfTop, the stairs will be upside down.
+func NewStairs(id BlockID, f Facing, half Half) (blk Block, ok bool) {
+	blk.ID = id
+	ok = blk.SetFacing(f) && blk.SetHalf(half)
+	return
+}
+
+// NewSlab creates a slab of the given ID. variant is the type of the slab (e.g. the material of stone slabs, the wood type of wooden slabs).
+func NewSlab(id BlockID, variant byte, half Half) (blk Block, ok bool) {
+	blk.ID = id
+	blk.Data = variant & 7
+	ok = blk.SetHalf(half)
+	return
+}
+
+// NewDoor creates the lower and upper block of a door.
+func NewDoor(id BlockID, f Facing, hinge Hinge) (lower, upper Block, ok bool) {
+	if !isOneOf(id, doorIDs) {
+		return Block{}, Block{}, false
+	}
+
+	lower.ID = id
+	upper.ID = id
+	upper.Data = 8 | byte(hinge&1)
+	ok = lower.SetFacing(f)
+	return
+}
@@ -0,0 +1,289 @@
+package mcmap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BlockState is a block as stored in chunks since Minecraft 1.13: a namespaced name and a set of properties,
+// e.g. "minecraft:oak_stairs[facing=north,half=top]".
+//
+// BlockStates are shared between blocks, so you must not modify them. Create a new one instead.
+type BlockState struct {
+	Name       string
+	Properties map[string]string
+}
+
+var (
+	InvalidBlockState = errors.New("Invalid block state")
+)
+
+// NewBlockState creates a new block state. The name gets normalized (see NormalizeBlockName), props may be nil.
+func NewBlockState(name string, props map[string]string) *BlockState {
+	return &BlockState{Name: NormalizeBlockName(name), Properties: props}
+}
+
+// ParseBlockState parses a block state in the format used by the game, e.g. "minecraft:oak_stairs[facing=north,half=top]" or "stone".
+func ParseBlockState(s string) (*BlockState, error) {
+	s = strings.TrimSpace(s)
+
+	name, propStr := s, ""
+	if i := strings.Index(s, "["); i >= 0 {
+		if !strings.HasSuffix(s, "]") {
+			return nil, InvalidBlockState
+		}
+		name, propStr = s[:i], s[i+1:len(s)-1]
+	}
+	if name == "" {
+		return nil, InvalidBlockState
+	}
+
+	bs := NewBlockState(name, nil)
+	if propStr == "" {
+		return bs, nil
+	}
+
+	bs.Properties = make(map[string]string)
+	for _, prop := range strings.Split(propStr, ",") {
+		kv := strings.SplitN(prop, "=", 2)
+		if len(kv) != 2 {
+			return nil, InvalidBlockState
+		}
+		bs.Properties[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return bs, nil
+}
+
+// String formats the block state in the format accepted by ParseBlockState. The properties are sorted by name.
+func (bs *BlockState) String() string {
+	if len(bs.Properties) == 0 {
+		return bs.Name
+	}
+
+	keys := make([]string, 0, len(bs.Properties))
+	for k := range bs.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	props := make([]string, len(keys))
+	for i, k := range keys {
+		props[i] = k + "=" + bs.Properties[k]
+	}
+	return bs.Name + "[" + strings.Join(props, ",") + "]"
+}
+
+// Property returns the value of a property or an empty string, if the property is not set.
+func (bs *BlockState) Property(name string) string { return bs.Properties[name] }
+
+// With returns a copy of the block state with a property set to a value.
+func (bs *BlockState) With(name, value string) *BlockState {
+	props := make(map[string]string, len(bs.Properties)+1)
+	for k, v := range bs.Properties {
+		props[k] = v
+	}
+	props[name] = value
+	return &BlockState{Name: bs.Name, Properties: props}
+}
+
+// Equal compares two block states.
+func (bs *BlockState) Equal(other *BlockState) bool {
+	if bs.Name != other.Name || len(bs.Properties) != len(other.Properties) {
+		return false
+	}
+	for k, v := range bs.Properties {
+		if ov, ok := other.Properties[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+// hasProperties checks, if all properties of sub are also set in bs with the same value.
+func (bs *BlockState) hasProperties(sub map[string]string) bool {
+	for k, v := range sub {
+		if bs.Properties[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func blockStateFromNBT(tc nbt.TagCompound) (*BlockState, error) {
+	name, err := tc.GetString("Name")
+	if err != nil {
+		return nil, fmt.Errorf("Could not read Name tag of block state: %s", err)
+	}
+	bs := &BlockState{Name: name}
+
+	props, err := tc.GetCompound("Properties")
+	switch err {
+	case nil:
+		bs.Properties = make(map[string]string, len(props))
+		for k := range props {
+			v, err := props.GetString(k)
+			if err != nil {
+				return nil, fmt.Errorf("Could not read property %s of block state: %s", k, err)
+			}
+			bs.Properties[k] = v
+		}
+	case nbt.NotFound:
+	default:
+		return nil, fmt.Errorf("Could not read Properties tag of block state: %s", err)
+	}
+
+	return bs, nil
+}
+
+func (bs *BlockState) toNBT() nbt.TagCompound {
+	tc := nbt.TagCompound{"Name": nbt.Tag{nbt.TAG_String, bs.Name}}
+	if len(bs.Properties) > 0 {
+		props := make(nbt.TagCompound, len(bs.Properties))
+		for k, v := range bs.Properties {
+			props[k] = nbt.Tag{nbt.TAG_String, v}
+		}
+		tc["Properties"] = nbt.Tag{nbt.TAG_Compound, props}
+	}
+	return tc
+}
+
+// firstDynamicBlockID is the first ID given to block states without a numeric equivalent (see BlockState.Legacy).
+const firstDynamicBlockID = maxBlockID + 1
+
+var dynamicBlockIDsLock sync.Mutex
+
+// dynamicBlockID returns the ID of a block without a numeric ID. A new ID gets registered in DefaultBlocks, if needed.
+func dynamicBlockID(name string) BlockID {
+	dynamicBlockIDsLock.Lock()
+	defer dynamicBlockIDsLock.Unlock()
+
+	if id, ok := DefaultBlocks.ID(name); ok && id >= firstDynamicBlockID {
+		return id
+	}
+
+	id := BlockID(firstDynamicBlockID)
+	for {
+		if _, ok := DefaultBlocks.Name(id); !ok {
+			break
+		}
+		id++
+	}
+	DefaultBlocks.Register(id, name)
+	return id
+}
+
+type legacyStateEntry struct {
+	props map[string]string
+	id    BlockID
+	data  byte
+}
+
+var (
+	legacyStatesOnce sync.Once
+	legacyStates     map[string][]legacyStateEntry // Indexed by name
+)
+
+func initLegacyStates() {
+	legacyStates = make(map[string][]legacyStateEntry)
+	for i := BlockID(0); i <= 0xff; i++ {
+		// Prefer stationary water / lava over their flowing variants.
+		id := i
+		switch i {
+		case BlkWater, BlkLava:
+			id = i + 1
+		case BlkStationaryWater, BlkStationaryLava:
+			id = i - 1
+		}
+
+		for data := byte(0); data <= 0xf; data++ {
+			if id == BlkGrass && data == 0 {
+				continue // Dead shrub, prefer BlkDeadBush
+			}
+
+			bs := legacyBlockState(id, data)
+			if bs == nil {
+				continue
+			}
+			legacyStates[bs.Name] = append(legacyStates[bs.Name], legacyStateEntry{bs.Properties, id, data})
+		}
+	}
+
+	// The rotation of skulls is stored in the tile entity.
+	for i := range legacyStates["minecraft:skeleton_skull"] {
+		legacyStates["minecraft:skeleton_skull"][i].props = nil
+	}
+
+	// States that differ only in their tile entity share the entries of the default state.
+	for name, aliases := range tileEntityStateAliases() {
+		entries := legacyStates["minecraft:"+name]
+		for _, alias := range aliases {
+			legacyStates["minecraft:"+alias] = append(legacyStates["minecraft:"+alias], entries...)
+		}
+	}
+
+	// The air variants added in 1.13 are plain air for us, the block state keeps the name.
+	for _, name := range []string{"minecraft:cave_air", "minecraft:void_air"} {
+		legacyStates[name] = legacyStates["minecraft:air"]
+	}
+}
+
+// Legacy returns the numeric block ID and data value (as used before Minecraft 1.13) of a block state.
+//
+// Block states without an equivalent (blocks added in later versions or by mods) get a dynamically
+// allocated ID above 4095 that is registered in DefaultBlocks. These IDs are only valid while the program runs.
+func (bs *BlockState) Legacy() (BlockID, byte) {
+	legacyStatesOnce.Do(initLegacyStates)
+
+	// Since the legacy states only contain the properties determined by the data value,
+	// the first entry whose properties are a subset of our properties matches.
+	for _, entry := range legacyStates[bs.Name] {
+		if bs.hasProperties(entry.props) {
+			return entry.id, entry.data
+		}
+	}
+
+	// Properties not matching any legacy variant. Use the ID without data value.
+	if entries := legacyStates[bs.Name]; len(entries) > 0 {
+		return entries[0].id, 0
+	}
+
+	return dynamicBlockID(bs.Name), 0
+}
+
+// BlockState returns the block state of the block. If b.State is set and matches ID and Data (see SetState), it will be returned.
+// Otherwise the state gets calculated from ID and Data.
+func (b Block) BlockState() *BlockState {
+	if b.State != nil {
+		if id, data := b.State.Legacy(); id == b.ID && data == b.Data&0xf {
+			return b.State
+		}
+	}
+
+	if bs := legacyBlockState(b.ID, b.Data&0xf); bs != nil {
+		return bs
+	}
+
+	if name, ok := DefaultBlocks.Name(b.ID); ok {
+		return &BlockState{Name: name}
+	}
+	return &BlockState{Name: "minecraft:air"}
+}
+
+// blockKey identifies the block state of a block (see Block.BlockState) for caching.
+type blockKey struct {
+	id    BlockID
+	data  byte
+	state *BlockState
+}
+
+func stateKey(blk Block) blockKey { return blockKey{blk.ID, blk.Data & 0xf, blk.State} }
+
+// SetState sets the block state of a block and updates ID and Data accordingly.
+func (b *Block) SetState(bs *BlockState) {
+	b.State = bs
+	b.ID, b.Data = bs.Legacy()
+}
@@ -1,26 +1,10 @@
 package mcmap
 
 import (
-	"errors"
 	"github.com/silvasur/gonbt/nbt"
 	"time"
 )
 
-func calcBlockOffset(x, y, z int) int {
-	if (x < 0) || (y < 0) || (z < 0) || (x >= ChunkSizeXZ) || (y >= ChunkSizeY) || (z >= ChunkSizeXZ) {
-		return -1
-	}
-
-	return x | (z << 4) | (y << 8)
-}
-
-func offsetToPos(off int) (x, y, z int) {
-	x = off & 0xf
-	z = (off >> 4) & 0xf
-	y = (off >> 8) & 0xff
-	return
-}
-
 // BlockToChunk calculates the chunk (cx, cz) and the block position in this chunk(rbx, rbz) of a block position given global coordinates.
 func BlockToChunk(bx, bz int) (cx, cz, rbx, rbz int) {
 	cx = bx >> 4
@@ -37,9 +21,16 @@ func ChunkToBlock(cx, cz, rbx, rbz int) (bx, bz int) {
 	return
 }
 
-// Chunk represents a 16*16*256 Chunk of the region.
+// Chunk represents a 16*16*N Chunk of the region. Before Minecraft 1.18, chunks are 256 blocks high.
+// Since 1.18, the height and the lowest Y coordinate depend on the dimension (see MinY and MaxY).
 type Chunk struct {
-	Entities []nbt.TagCompound
+	// Entities in this chunk. When the chunk is saved, entities whose position is in another chunk of the region are moved there.
+	// Entities read without a position stay in the chunk, entities without an id tag are kept unchanged with an empty ID.
+	// (Tile entities belong to their block, see Block.TileEntity.)
+	Entities []*Entity
+
+	// Points of interest in this chunk (Minecraft 1.14+). Only available, if the region has a poi folder (see Region.SetEntityAndPOIFolders).
+	POIs []PointOfInterest
 
 	x, z int32
 
@@ -48,50 +39,244 @@ type Chunk struct {
 	inhabitedTime int64
 	ts            time.Time
 
-	heightMap []int32 // Ordered ZX
+	heightMaps      [numHeightMapTypes][]int32 // Ordered ZX
+	heightMapsValid bool
 
 	modified bool
+	minY     int     // Lowest Y coordinate, multiple of 16
+	sizeY    int     // Height, multiple of 16
 	blocks   []Block // Ordered YZX
-	biomes   []Biome // Ordered ZX
+	biomes   []Biome // Ordered ZX, or YZX cells of 4x4x4 blocks, if biomes3D is set
+	biomes3D bool    // Biomes are stored in 3D (1.15+)
+
+	dataVersion   int32
+	status        string                  // Generation status (1.13+)
+	extra         nbt.TagCompound         // Level tags not interpreted by us (1.13+)
+	extraSections []nbt.TagCompound       // Sections outside of the world, containing only light data (1.13+)
+	rootExtra     nbt.TagCompound         // Root tags next to the Level compound not interpreted by us (before 1.18)
+	sectionExtra  map[int]nbt.TagCompound // Section tags not interpreted by us, indexed by section Y
+	missingLight  map[int]byte            // Light arrays missing in the sections (noBlockLight, noSkyLight), indexed by section Y (1.13+)
+
+	entitiesSeparate bool                    // Entities are stored in the entities folder (1.17+)
+	poiSections      map[int]nbt.TagCompound // Sections of the chunk in the poi folder without the records, indexed by section Y
+	fluidTicks       map[int]*TileTick       // Scheduled fluid updates (1.13+), indexed by block offset
+	stringTickIDs    bool                    // The scheduled updates were read with block names (before 1.13)
 
 	deleted bool
 
 	reg *Region
 }
 
+func (c *Chunk) blockOffset(x, y, z int) int {
+	y -= c.minY
+	if (x < 0) || (y < 0) || (z < 0) || (x >= ChunkSizeXZ) || (y >= c.sizeY) || (z >= ChunkSizeXZ) {
+		return -1
+	}
+
+	return x | (z << 4) | (y << 8)
+}
+
+func (c *Chunk) offsetToPos(off int) (x, y, z int) {
+	x = off & 0xf
+	z = (off >> 4) & 0xf
+	y = (off >> 8) + c.minY
+	return
+}
+
+// biomeCellOffset calculates the offset of the biome cell containing x, y, z. y gets clamped to the range of the chunk.
+func (c *Chunk) biomeCellOffset(x, y, z int) int {
+	cy := (y - c.minY) >> 2
+	if cy < 0 {
+		cy = 0
+	} else if max := c.sizeY/biomeCellSize - 1; cy > max {
+		cy = max
+	}
+	return (x >> 2) | ((z >> 2) << 2) | (cy << 4)
+}
+
+// initBiomes allocates the biomes (in 3D for 1.15+ chunks) and sets them to BioUncalculated.
+func (c *Chunk) initBiomes() {
+	c.biomes3D = c.dataVersion >= dataVersion3DBiomes
+	n := ChunkRectXZ
+	if c.biomes3D {
+		n = biomeCellsXZ * c.sizeY / biomeCellSize
+	}
+
+	c.biomes = make([]Biome, n)
+	for i := range c.biomes {
+		c.biomes[i] = BioUncalculated
+	}
+}
+
+// setRange sets the vertical range of the chunk and allocates the blocks (air with full sky light).
+func (c *Chunk) setRange(minY, sizeY int) {
+	c.minY = minY
+	c.sizeY = sizeY
+	c.blocks = make([]Block, ChunkRectXZ*sizeY)
+	for i := range c.blocks {
+		c.blocks[i].SkyLight = maxLight
+	}
+}
+
 func newChunk(reg *Region, x, z int) *Chunk {
-	biomes := make([]Biome, ChunkRectXZ)
-	for i := range biomes {
-		biomes[i] = BioUncalculated
+	dataVersion, minY, sizeY := int32(0), 0, ChunkSizeY
+	if reg != nil && reg.sizeY > 0 {
+		dataVersion, minY, sizeY = reg.dataVersion, reg.minY, reg.sizeY
+	}
+
+	c := &Chunk{
+		x:           int32(x),
+		z:           int32(z),
+		ts:          time.Now(),
+		dataVersion: dataVersion,
+		reg:         reg,
+	}
+	c.setRange(minY, sizeY)
+	c.initBiomes()
+	if dataVersion >= dataVersionFlattening {
+		c.status = "full"
+	}
+	c.entitiesSeparate = reg != nil && reg.entities != nil && dataVersion >= dataVersionSeparateEntities
+	return c
+}
+
+// copyFrom replaces the content of c with a copy of the content of src.
+func (c *Chunk) copyFrom(src *Chunk) {
+	c.Entities = make([]*Entity, len(src.Entities))
+	for i, ent := range src.Entities {
+		cp := *ent
+		cp.NBT = deepCopyCompound(ent.NBT)
+		c.Entities[i] = &cp
+	}
+	c.POIs = append([]PointOfInterest(nil), src.POIs...)
+
+	c.lastUpdate = src.lastUpdate
+	c.populated = src.populated
+	c.inhabitedTime = src.inhabitedTime
+	c.ts = src.ts
+
+	for t := range c.heightMaps {
+		c.heightMaps[t] = append([]int32(nil), src.heightMaps[t]...)
+	}
+	c.heightMapsValid = src.heightMapsValid
+	c.minY = src.minY
+	c.sizeY = src.sizeY
+	c.blocks = append([]Block(nil), src.blocks...)
+	for i := range c.blocks {
+		blk := &c.blocks[i]
+		blk.TileEntity = deepCopyCompound(blk.TileEntity)
+		if blk.Tick != nil {
+			tick := *blk.Tick
+			blk.Tick = &tick
+		}
+	}
+	c.biomes = append([]Biome(nil), src.biomes...)
+	c.biomes3D = src.biomes3D
+
+	c.dataVersion = src.dataVersion
+	c.status = src.status
+	c.extra = deepCopyCompound(src.extra)
+	c.extraSections = nil
+	for _, section := range src.extraSections {
+		c.extraSections = append(c.extraSections, deepCopyCompound(section))
+	}
+	c.rootExtra = deepCopyCompound(src.rootExtra)
+	c.sectionExtra = nil
+	if src.sectionExtra != nil {
+		c.sectionExtra = make(map[int]nbt.TagCompound, len(src.sectionExtra))
+		for y, extra := range src.sectionExtra {
+			c.sectionExtra[y] = deepCopyCompound(extra)
+		}
+	}
+	c.missingLight = nil
+	if src.missingLight != nil {
+		c.missingLight = make(map[int]byte, len(src.missingLight))
+		for y, missing := range src.missingLight {
+			c.missingLight[y] = missing
+		}
 	}
+	c.stringTickIDs = src.stringTickIDs
+	c.fluidTicks = nil
+	if src.fluidTicks != nil {
+		c.fluidTicks = make(map[int]*TileTick, len(src.fluidTicks))
+		for off, tick := range src.fluidTicks {
+			cp := *tick
+			c.fluidTicks[off] = &cp
+		}
+	}
+	c.poiSections = nil
+	if src.poiSections != nil {
+		c.poiSections = make(map[int]nbt.TagCompound, len(src.poiSections))
+		for y, section := range src.poiSections {
+			c.poiSections[y] = deepCopyCompound(section)
+		}
+	}
+}
+
+// copyCompound creates a shallow copy of a compound (nil stays nil).
+func copyCompound(tc nbt.TagCompound) nbt.TagCompound {
+	if tc == nil {
+		return nil
+	}
+	cp := make(nbt.TagCompound, len(tc))
+	for name, tag := range tc {
+		cp[name] = tag
+	}
+	return cp
+}
 
-	heightMap := make([]int32, ChunkRectXZ)
-	for i := range heightMap {
-		heightMap[i] = ChunkSizeY - 1
+// deepCopyCompound creates a copy of a compound including all nested compounds, lists and arrays (nil stays nil).
+func deepCopyCompound(tc nbt.TagCompound) nbt.TagCompound {
+	if tc == nil {
+		return nil
 	}
+	cp := make(nbt.TagCompound, len(tc))
+	for name, tag := range tc {
+		cp[name] = nbt.Tag{tag.Type, deepCopyPayload(tag.Payload)}
+	}
+	return cp
+}
 
-	return &Chunk{
-		x:         int32(x),
-		z:         int32(z),
-		ts:        time.Now(),
-		blocks:    make([]Block, ChunkSize),
-		biomes:    biomes,
-		heightMap: heightMap,
-		reg:       reg,
+func deepCopyPayload(payload interface{}) interface{} {
+	switch p := payload.(type) {
+	case nbt.TagCompound:
+		return deepCopyCompound(p)
+	case nbt.TagList:
+		elems := make([]interface{}, len(p.Elems))
+		for i, elem := range p.Elems {
+			elems[i] = deepCopyPayload(elem)
+		}
+		return nbt.TagList{p.Type, elems}
+	case []byte:
+		return append([]byte(nil), p...)
+	case []int32:
+		return append([]int32(nil), p...)
+	case []int64:
+		return append([]int64(nil), p...)
 	}
+	return payload
 }
 
 // MarkModified needs to be called, if some data of the chunk was modified.
-func (c *Chunk) MarkModified() { c.modified = true }
+//
+// The height maps will be recalculated, when they are needed the next time.
+func (c *Chunk) MarkModified() {
+	c.modified = true
+	c.heightMapsValid = false
+}
+
+// DataVersion returns the data version of the chunk, i.e. the Minecraft version that saved it (0 for chunks from versions before 1.9).
+// The chunk format and codec (see ChunkCodec) depend on it.
+func (c *Chunk) DataVersion() int32 { return c.dataVersion }
 
 // Coords returns the Chunk's coordinates.
 func (c *Chunk) Coords() (X, Z int32) { return c.x, c.z }
 
 // Block gives you a reference to the Block located at x, y, z. If you modify the block data, you need to call the MarkModified() function of the chunk.
 //
-// x and z must be in [0, 15], y in [0, 255]. Otherwise a nil pointer is returned.
+// x and z must be in [0, 15], y in [MinY(), MaxY()]. Otherwise a nil pointer is returned.
 func (c *Chunk) Block(x, y, z int) *Block {
-	off := calcBlockOffset(x, y, z)
+	off := c.blockOffset(x, y, z)
 	if off < 0 {
 		return nil
 	}
@@ -99,33 +284,105 @@ func (c *Chunk) Block(x, y, z int) *Block {
 	return &(c.blocks[off])
 }
 
-// Height returns the height at x, z.
+// MinY returns the lowest Y coordinate of the chunk (0 before Minecraft 1.18).
+func (c *Chunk) MinY() int { return c.minY }
+
+// MaxY returns the highest Y coordinate of the chunk (255 before Minecraft 1.18).
+func (c *Chunk) MaxY() int { return c.minY + c.sizeY - 1 }
+
+// Height returns the height at x, z, i.e. the Y coordinate of the highest block absorbing light (MinY()-1, if there is none).
+// Note that height maps (see HeightOf) contain the Y coordinate above that block.
 //
 // x and z must be in [0, 15]. Height will panic, if this is violated!
-func (c *Chunk) Height(x, z int) int {
-	if (x < 0) || (x >= ChunkSizeXZ) || (z < 0) || (z >= ChunkSizeXZ) {
-		panic(errors.New("x or z parameter was out of range"))
-	}
-
-	return int(c.heightMap[z*ChunkSizeXZ+x])
-}
+func (c *Chunk) Height(x, z int) int { return c.HeightOf(HeightMapLightBlocking, x, z) - 1 }
 
 // Iter iterates ofer all blocks of this chunk and calls the function fx with the coords (x,y,z) and a pointer to the block.
 func (c *Chunk) Iter(fx func(int, int, int, *Block)) {
 	for x := 0; x < ChunkSizeXZ; x++ {
-		for y := 0; y < ChunkSizeY; y++ {
+		for y := c.MinY(); y <= c.MaxY(); y++ {
 			for z := 0; z < ChunkSizeXZ; z++ {
-				fx(x, y, z, &(c.blocks[calcBlockOffset(x, y, z)]))
+				fx(x, y, z, &(c.blocks[c.blockOffset(x, y, z)]))
 			}
 		}
 	}
 }
 
-// Biome gets the Biome at x,z.
-func (c *Chunk) Biome(x, z int) Biome { return c.biomes[z*ChunkSizeXZ+x] }
+// ExtraTags returns the tags of the chunk that are not interpreted by gomcmap (e.g. Structures or the data of mods).
+// They are saved together with the chunk. If you modify them, you need to call MarkModified.
+//
+// Before Minecraft 1.18 these are the tags of the Level compound, use ExtraRootTags for the tags next to it.
+func (c *Chunk) ExtraTags() nbt.TagCompound {
+	if c.extra == nil {
+		c.extra = make(nbt.TagCompound)
+	}
+	return c.extra
+}
 
-// SetBiome sets the biome at x,z.
-func (c *Chunk) SetBiome(x, z int, bio Biome) { c.biomes[z*ChunkSizeXZ+x] = bio }
+// ExtraRootTags returns the tags of the root compound next to the Level compound that are not interpreted by gomcmap.
+// Since Minecraft 1.18 there is no Level compound, use ExtraTags instead.
+func (c *Chunk) ExtraRootTags() nbt.TagCompound {
+	if c.rootExtra == nil {
+		c.rootExtra = make(nbt.TagCompound)
+	}
+	return c.rootExtra
+}
+
+// ExtraSectionTags returns the tags of the section (16 blocks high, starting at Y = y*16) that are not interpreted by gomcmap.
+// If you modify them, you need to call MarkModified.
+func (c *Chunk) ExtraSectionTags(y int) nbt.TagCompound {
+	if c.sectionExtra == nil {
+		c.sectionExtra = make(map[int]nbt.TagCompound)
+	}
+	if c.sectionExtra[y] == nil {
+		c.sectionExtra[y] = make(nbt.TagCompound)
+	}
+	return c.sectionExtra[y]
+}
+
+// Has3DBiomes checks, if the biomes of the chunk are stored in 3D (since Minecraft 1.15).
+// 3D biomes are stored for cells of 4x4x4 blocks.
+func (c *Chunk) Has3DBiomes() bool { return c.biomes3D }
+
+// Biome gets the Biome at x,z. For chunks with 3D biomes, this is the biome at the surface (see HeightMapWorldSurface).
+func (c *Chunk) Biome(x, z int) Biome {
+	if !c.biomes3D {
+		return c.biomes[z*ChunkSizeXZ+x]
+	}
+	return c.biomes[c.biomeCellOffset(x, c.HeightOf(HeightMapWorldSurface, x, z)-1, z)]
+}
+
+// SetBiome sets the biome at x,z. For chunks with 3D biomes, the biome of the whole column of 4x4 blocks containing x,z is set.
+func (c *Chunk) SetBiome(x, z int, bio Biome) {
+	if !c.biomes3D {
+		c.biomes[z*ChunkSizeXZ+x] = bio
+		return
+	}
+	for off := c.biomeCellOffset(x, c.minY, z); off < len(c.biomes); off += biomeCellsXZ {
+		c.biomes[off] = bio
+	}
+}
+
+// BiomeAt gets the biome at x,y,z. For chunks with 2D biomes, y is ignored.
+//
+// x and z must be in [0, 15]. Y coordinates outside of the chunk are clamped.
+func (c *Chunk) BiomeAt(x, y, z int) Biome {
+	if !c.biomes3D {
+		return c.biomes[z*ChunkSizeXZ+x]
+	}
+	return c.biomes[c.biomeCellOffset(x, y, z)]
+}
+
+// SetBiomeAt sets the biome at x,y,z. For chunks with 3D biomes, the biome of the whole 4x4x4 cell containing x,y,z is set,
+// for chunks with 2D biomes the biome of the column.
+//
+// x and z must be in [0, 15]. Y coordinates outside of the chunk are clamped.
+func (c *Chunk) SetBiomeAt(x, y, z int, bio Biome) {
+	if !c.biomes3D {
+		c.biomes[z*ChunkSizeXZ+x] = bio
+		return
+	}
+	c.biomes[c.biomeCellOffset(x, y, z)] = bio
+}
 
 // MarkUnused marks the chunk as unused. If all chunks of a superchunk are marked as unused, the underlying superchunk will be unloaded and saved (if needed).
 //
@@ -136,23 +393,3 @@ func (c *Chunk) MarkUnused() error { return c.reg.unloadChunk(int(c.x), int(c.z)
 
 // MarkDeleted marks this chunk as deleted. After marking it as unused, it will be deleted and can no longer be used.
 func (c *Chunk) MarkDeleted() { c.deleted = true }
-
-// RecalcHeightMap recalculates the internal height map.
-//
-// You should use this function before marking the chunk as unused, if you modified the chunk
-// (unless you know, your changes wouldn't affect the height map).
-func (c *Chunk) RecalcHeightMap() {
-	i := 0
-	for z := 0; z < ChunkSizeXZ; z++ {
-		for x := 0; x < ChunkSizeXZ; x++ {
-			for y := ChunkSizeY - 1; y >= 0; y-- {
-				blkid := c.blocks[calcBlockOffset(x, y, z)].ID
-				if (blkid != BlkAir) && (blkid != BlkGlass) && (blkid != BlkGlassPane) {
-					c.heightMap[i] = int32(y)
-					break
-				}
-			}
-			i++
-		}
-	}
-}
@@ -0,0 +1,123 @@
+package mcmap
+
+import (
+	"strings"
+	"time"
+)
+
+// ChunkStatus is the generation status of a chunk (Minecraft 1.13+). Since Minecraft 1.20 it can have a "minecraft:" prefix.
+type ChunkStatus string
+
+// Generation statuses of chunks since Minecraft 1.14, in the order in which they are reached.
+const (
+	StatusEmpty               ChunkStatus = "empty"
+	StatusStructureStarts     ChunkStatus = "structure_starts"
+	StatusStructureReferences ChunkStatus = "structure_references"
+	StatusBiomes              ChunkStatus = "biomes"
+	StatusNoise               ChunkStatus = "noise"
+	StatusSurface             ChunkStatus = "surface"
+	StatusCarvers             ChunkStatus = "carvers"
+	StatusLiquidCarvers       ChunkStatus = "liquid_carvers"
+	StatusFeatures            ChunkStatus = "features"
+	StatusInitializeLight     ChunkStatus = "initialize_light" // Minecraft 1.20+
+	StatusLight               ChunkStatus = "light"
+	StatusSpawn               ChunkStatus = "spawn"
+	StatusHeightmaps          ChunkStatus = "heightmaps"
+	StatusFull                ChunkStatus = "full"
+)
+
+// Chunk statuses of Minecraft 1.13, replaced in 1.14.
+var legacyStatuses = map[string]bool{
+	"base":          true,
+	"carved":        true,
+	"liquid_carved": true,
+	"decorated":     true,
+	"lighted":       true,
+	"mobs_spawned":  true,
+	"finalized":     true,
+	"fullchunk":     true,
+	"postprocessed": true,
+}
+
+func (s ChunkStatus) name() string { return strings.TrimPrefix(string(s), "minecraft:") }
+
+// Populated checks, if the terrain of a chunk with this status was populated (decorated with ores, trees, ...).
+func (s ChunkStatus) Populated() bool { return populatedStatuses[s.name()] }
+
+// LastUpdate returns the game tick, in which the chunk was last saved by Minecraft.
+func (c *Chunk) LastUpdate() int64 { return c.lastUpdate }
+
+// SetLastUpdate sets the game tick, in which the chunk was last saved and marks the chunk as modified.
+func (c *Chunk) SetLastUpdate(tick int64) {
+	c.lastUpdate = tick
+	c.MarkModified()
+}
+
+// InhabitedTime returns the number of ticks players spent in this chunk (accumulated over all players).
+// Minecraft uses this to increase the local difficulty.
+func (c *Chunk) InhabitedTime() int64 { return c.inhabitedTime }
+
+// SetInhabitedTime sets the number of ticks players spent in this chunk and marks the chunk as modified.
+func (c *Chunk) SetInhabitedTime(ticks int64) {
+	c.inhabitedTime = ticks
+	c.MarkModified()
+}
+
+// Populated checks, if the terrain of the chunk was populated (decorated with ores, trees, ...).
+// For 1.13+ chunks this is derived from the status (see Status).
+func (c *Chunk) Populated() bool { return c.populated }
+
+// SetPopulated sets, if the terrain of the chunk was populated and marks the chunk as modified.
+// Minecraft will populate unpopulated chunks again, when they are loaded.
+//
+// For 1.13+ chunks, the status is changed to the last status before or after the decoration, if needed.
+func (c *Chunk) SetPopulated(populated bool) {
+	if c.dataVersion >= dataVersionFlattening && populated != c.populated {
+		prefix := ""
+		if strings.HasPrefix(c.status, "minecraft:") {
+			prefix = "minecraft:"
+		}
+
+		var status ChunkStatus
+		switch legacy := legacyStatuses[ChunkStatus(c.status).name()]; {
+		case populated && legacy:
+			status = "postprocessed"
+		case populated:
+			status = StatusFull
+		case legacy:
+			status = "liquid_carved"
+		default:
+			status = StatusLiquidCarvers
+		}
+		c.status = prefix + string(status)
+	}
+
+	c.populated = populated
+	c.MarkModified()
+}
+
+// Status returns the generation status of the chunk. Chunks from versions before Minecraft 1.13 have no status (empty string).
+func (c *Chunk) Status() ChunkStatus { return ChunkStatus(c.status) }
+
+// SetStatus sets the generation status of the chunk, updates the populated flag (see Populated) accordingly
+// and marks the chunk as modified.
+//
+// This has no effect on chunks from versions before Minecraft 1.13.
+func (c *Chunk) SetStatus(status ChunkStatus) {
+	if c.dataVersion < dataVersionFlattening {
+		return
+	}
+
+	c.status = string(status)
+	c.populated = status.Populated()
+	c.MarkModified()
+}
+
+// Timestamp returns the time, when the chunk was last saved (as stored in the region file).
+func (c *Chunk) Timestamp() time.Time { return c.ts }
+
+// SetTimestamp sets the time that is stored in the region file, when the chunk is saved, and marks the chunk as modified.
+func (c *Chunk) SetTimestamp(t time.Time) {
+	c.ts = t
+	c.MarkModified()
+}
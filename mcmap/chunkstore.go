@@ -0,0 +1,224 @@
+package mcmap
+
+import (
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// This file implements the additional region folders of newer Minecraft versions, that use the region file format
+// but contain other data than block chunks: Points of interest (poi/, since Minecraft 1.14) and entities (entities/, since Minecraft 1.17).
+
+// regionFileName returns the name of the region file of the superchunk at pos in the directory dir.
+func regionFileName(dir string, pos XZPos) string {
+	return filepath.Join(dir, fmt.Sprintf("r.%d.%d.mca", pos.X, pos.Z))
+}
+
+type storeSuperchunk struct {
+	preChunks map[XZPos]*preChunk
+	modified  bool
+}
+
+// chunkStore gives access to the per-chunk NBT data of a region folder.
+type chunkStore struct {
+	path        string
+	superchunks map[XZPos]*storeSuperchunk
+}
+
+func newChunkStore(path string) *chunkStore {
+	return &chunkStore{
+		path:        path,
+		superchunks: make(map[XZPos]*storeSuperchunk),
+	}
+}
+
+func (s *chunkStore) superchunk(scPos XZPos) (*storeSuperchunk, error) {
+	if sc, ok := s.superchunks[scPos]; ok {
+		return sc, nil
+	}
+
+	sc := &storeSuperchunk{preChunks: make(map[XZPos]*preChunk)}
+	f, err := os.Open(regionFileName(s.path, scPos))
+	switch {
+	case err == nil:
+		defer f.Close()
+		if sc.preChunks, err = readRegionFile(f); err != nil {
+			return nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	s.superchunks[scPos] = sc
+	return sc, nil
+}
+
+// get returns the root compound of the chunk at cx, cz or the error NotAvailable.
+func (s *chunkStore) get(cx, cz int) (nbt.TagCompound, error) {
+	scx, scz, rx, rz := chunkToSuperchunk(cx, cz)
+	sc, err := s.superchunk(XZPos{scx, scz})
+	if err != nil {
+		return nil, err
+	}
+
+	pc, ok := sc.preChunks[XZPos{rx, rz}]
+	if !ok {
+		return nil, NotAvailable
+	}
+	return pc.getRootTag()
+}
+
+// put sets the root compound of the chunk at cx, cz. A nil compound removes the chunk.
+func (s *chunkStore) put(cx, cz int, root nbt.TagCompound) error {
+	scx, scz, rx, rz := chunkToSuperchunk(cx, cz)
+	sc, err := s.superchunk(XZPos{scx, scz})
+	if err != nil {
+		return err
+	}
+
+	pos := XZPos{rx, rz}
+	if root == nil {
+		if _, ok := sc.preChunks[pos]; !ok {
+			return nil
+		}
+		delete(sc.preChunks, pos)
+	} else {
+		pc, err := newPreChunk(root, time.Now())
+		if err != nil {
+			return err
+		}
+		sc.preChunks[pos] = pc
+	}
+	sc.modified = true
+	return nil
+}
+
+// flush saves the superchunk at scPos, if it was modified, and removes it from memory.
+func (s *chunkStore) flush(scPos XZPos) error {
+	sc, ok := s.superchunks[scPos]
+	if !ok {
+		return nil
+	}
+
+	if sc.modified {
+		fn := regionFileName(s.path, scPos)
+		if len(sc.preChunks) == 0 {
+			if err := os.Remove(fn); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		} else {
+			if err := os.MkdirAll(s.path, 0755); err != nil {
+				return err
+			}
+			f, err := os.Create(fn)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			if err := writeRegionFile(f, sc.preChunks); err != nil {
+				return err
+			}
+		}
+	}
+
+	delete(s.superchunks, scPos)
+	return nil
+}
+
+// SetEntityAndPOIFolders sets the region folders containing the entities (Minecraft 1.17+) and the points of interest (Minecraft 1.14+)
+// of the chunks. An empty path disables the folder. The folders don't need to exist, they are created when needed.
+//
+// The entities and points of interest are then available as Chunk.Entities and Chunk.POIs, like the entities of older chunks.
+// World.Region sets the folders of the dimension. Call this before loading any chunks.
+func (reg *Region) SetEntityAndPOIFolders(entitiesPath, poiPath string) {
+	reg.entities, reg.poi = nil, nil
+	if entitiesPath != "" {
+		reg.entities = newChunkStore(entitiesPath)
+	}
+	if poiPath != "" {
+		reg.poi = newChunkStore(poiPath)
+	}
+}
+
+// loadSeparateData loads the entities and points of interest of a chunk from the entities and poi folders.
+func (reg *Region) loadSeparateData(c *Chunk) error {
+	if reg.entities != nil && c.dataVersion >= dataVersionSeparateEntities {
+		c.entitiesSeparate = true
+
+		switch root, err := reg.entities.get(int(c.x), int(c.z)); err {
+		case nil:
+			if err := c.readEntities(root); err != nil {
+				return err
+			}
+		case NotAvailable:
+			c.Entities = []*Entity{}
+		default:
+			return err
+		}
+	}
+
+	if reg.poi != nil {
+		switch root, err := reg.poi.get(int(c.x), int(c.z)); err {
+		case nil:
+			if err := c.decodePOIs(root); err != nil {
+				return fmt.Errorf("Could not read points of interest: %s", err)
+			}
+		case NotAvailable:
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveSeparateData puts the entities and points of interest of a chunk into the entities and poi folders.
+func (reg *Region) saveSeparateData(c *Chunk) error {
+	if reg.entities != nil && c.entitiesSeparate {
+		var root nbt.TagCompound
+		if len(c.Entities) > 0 {
+			root = nbt.TagCompound{
+				"DataVersion": nbt.NewIntTag(c.dataVersion),
+				"Position":    nbt.NewIntArrayTag([]int32{c.x, c.z}),
+				"Entities":    c.entitiesTag(),
+			}
+		}
+		if err := reg.entities.put(int(c.x), int(c.z), root); err != nil {
+			return err
+		}
+	}
+
+	if reg.poi != nil {
+		if err := reg.poi.put(int(c.x), int(c.z), c.encodePOIs()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (reg *Region) deleteSeparateData(cx, cz int) error {
+	for _, store := range []*chunkStore{reg.entities, reg.poi} {
+		if store != nil {
+			if err := store.put(cx, cz, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flushStores saves and unloads the superchunk at scPos of the entities and poi folders.
+func (reg *Region) flushStores(scPos XZPos) error {
+	for _, store := range []*chunkStore{reg.entities, reg.poi} {
+		if store != nil {
+			if err := store.flush(scPos); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package mcmap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"sort"
+	"sync"
+)
+
+// ChunkCodec decodes and encodes the NBT data of chunks in one chunk format.
+//
+// The codec is selected by the DataVersion tag of the chunk (see RegisterChunkCodec).
+// A codec for a modified format can wrap one of the built-in codecs, e.g. by transforming the NBT data before decoding.
+type ChunkCodec interface {
+	// Decode reads the root compound of the chunk data into c.
+	// c is an empty chunk that only knows its region and its data version (see Chunk.DataVersion).
+	Decode(root nbt.TagCompound, c *Chunk) error
+
+	// Encode creates the root compound of the chunk data.
+	Encode(c *Chunk) (nbt.TagCompound, error)
+}
+
+var (
+	NoChunkCodec = errors.New("No chunk codec for this data version")
+)
+
+// The built-in chunk codecs.
+var (
+	// LegacyChunkCodec handles the Anvil format with numeric block IDs (before Minecraft 1.13).
+	LegacyChunkCodec ChunkCodec = levelCodec{flattened: false}
+
+	// FlattenedChunkCodec handles the block state format of Minecraft 1.13 - 1.17.
+	FlattenedChunkCodec ChunkCodec = levelCodec{flattened: true}
+
+	// ChunkCodec118 handles the format of Minecraft 1.18 and later, where the tags are stored in the root compound.
+	ChunkCodec118 ChunkCodec = rootCodec{}
+)
+
+type codecEntry struct {
+	minDataVersion int32
+	codec          ChunkCodec
+}
+
+var (
+	chunkCodecsLock sync.RWMutex
+	chunkCodecs     = []codecEntry{
+		{0, LegacyChunkCodec},
+		{dataVersionFlattening, FlattenedChunkCodec},
+		{dataVersionNoLevel, ChunkCodec118},
+	}
+)
+
+// RegisterChunkCodec registers a codec for chunks with a data version of at least minDataVersion
+// (up to the minDataVersion of the next codec). Chunks without a DataVersion tag have the data version 0.
+//
+// An already registered codec with the same minDataVersion is replaced, a nil codec removes it.
+func RegisterChunkCodec(minDataVersion int32, codec ChunkCodec) {
+	chunkCodecsLock.Lock()
+	defer chunkCodecsLock.Unlock()
+
+	codecs := make([]codecEntry, 0, len(chunkCodecs)+1)
+	for _, entry := range chunkCodecs {
+		if entry.minDataVersion != minDataVersion {
+			codecs = append(codecs, entry)
+		}
+	}
+	if codec != nil {
+		codecs = append(codecs, codecEntry{minDataVersion, codec})
+	}
+	sort.Slice(codecs, func(i, j int) bool { return codecs[i].minDataVersion < codecs[j].minDataVersion })
+
+	chunkCodecs = codecs
+}
+
+// ChunkCodecFor returns the codec used for chunks with the given data version.
+// If no codec is registered for the version, NoChunkCodec is returned.
+func ChunkCodecFor(dataVersion int32) (ChunkCodec, error) {
+	chunkCodecsLock.RLock()
+	defer chunkCodecsLock.RUnlock()
+
+	for i := len(chunkCodecs) - 1; i >= 0; i-- {
+		if chunkCodecs[i].minDataVersion <= dataVersion {
+			return chunkCodecs[i].codec, nil
+		}
+	}
+	return nil, NoChunkCodec
+}
+
+// Root tags of chunks in the formats with a Level compound, that are interpreted by levelCodec.
+var levelRootTags = map[string]bool{
+	"Level":       true,
+	"DataVersion": true,
+}
+
+// levelCodec handles the formats that store the chunk in the Level compound.
+type levelCodec struct {
+	flattened bool
+}
+
+func (lc levelCodec) Decode(root nbt.TagCompound, c *Chunk) error {
+	lvl, err := root.GetCompound("Level")
+	if err != nil {
+		return fmt.Errorf("Could not read Level tag: %s", err)
+	}
+	c.rootExtra = unknownTags(root, levelRootTags)
+	return c.decodeLevel(lvl, lc.flattened)
+}
+
+func (lc levelCodec) Encode(c *Chunk) (nbt.TagCompound, error) {
+	root := make(nbt.TagCompound)
+	for name, tag := range c.rootExtra {
+		root[name] = tag
+	}
+	root["Level"] = nbt.Tag{nbt.TAG_Compound, c.encodeLevel(lc.flattened)}
+	if c.dataVersion != 0 {
+		root["DataVersion"] = nbt.NewIntTag(c.dataVersion)
+	}
+	return root, nil
+}
+
+// rootCodec handles the format of Minecraft 1.18 and later.
+type rootCodec struct{}
+
+func (rootCodec) Decode(root nbt.TagCompound, c *Chunk) error {
+	return c.decodeLevel(root, true)
+}
+
+func (rootCodec) Encode(c *Chunk) (nbt.TagCompound, error) {
+	root := c.encodeLevel(true)
+	root["DataVersion"] = nbt.NewIntTag(c.dataVersion)
+	return root, nil
+}
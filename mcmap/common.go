@@ -3,3 +3,17 @@ package mcmap
 type XZPos struct {
 	X, Z int
 }
+
+// Data versions (stored in the DataVersion tag of chunks) of changes to the chunk format.
+const (
+	dataVersionNamespacedIDs     = 704  // 16w32a (Minecraft 1.11): Namespaced tile entity and entity IDs.
+	dataVersionFlattening        = 1451 // 17w47a (Minecraft 1.13): Block states with palettes.
+	dataVersion3DBiomes          = 2203 // 19w36a (Minecraft 1.15): Biomes are stored for cells of 4x4x4 blocks.
+	dataVersionIntArrayUUIDs     = 2513 // 20w12a (Minecraft 1.16): UUIDs are stored as int arrays.
+	dataVersionNonSpanningStates = 2529 // 20w17a (Minecraft 1.16): Block state indices no longer span multiple longs.
+	dataVersionSeparateEntities  = 2681 // 20w45a (Minecraft 1.17): Entities are stored in the entities folder.
+	dataVersionSpawnerEntity     = 2831 // 21w37a (Minecraft 1.18): The spawn data of spawners has an entity compound.
+	dataVersionNoLevel           = 2844 // 21w43a (Minecraft 1.18): No Level compound, renamed tags, negative Y.
+	dataVersionSignSides         = 3439 // 23w12a (Minecraft 1.20): Signs have text on both sides.
+	dataVersionItemComponents    = 3819 // 24w09a (Minecraft 1.20.5): Items have components instead of a tag compound.
+)
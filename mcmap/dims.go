@@ -2,10 +2,23 @@ package mcmap
 
 const (
 	ChunkSizeXZ = 16
-	ChunkSizeY  = 256
+	ChunkSizeY  = 256 // Height of chunks before Minecraft 1.18. Use Chunk.MinY / Chunk.MaxY instead.
 	ChunkRectXZ = ChunkSizeXZ * ChunkSizeXZ
 	ChunkSize   = ChunkRectXZ * ChunkSizeY
 )
 
 const superchunkSizeXZ = 32
 const chunkSectionSize = ChunkRectXZ * 16
+
+// Vertical range of the overworld since Minecraft 1.18.
+const (
+	overworldMinY118  = -64
+	overworldSizeY118 = 384
+)
+
+// Since Minecraft 1.15, biomes are stored for cells of 4x4x4 blocks.
+const (
+	biomeCellSize       = 4
+	biomeCellsXZ        = (ChunkSizeXZ / biomeCellSize) * (ChunkSizeXZ / biomeCellSize)
+	biomeCellsInSection = biomeCellsXZ * 16 / biomeCellSize
+)
@@ -0,0 +1,305 @@
+package mcmap
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"math"
+	"strings"
+)
+
+// UUID identifies an entity.
+type UUID [16]byte
+
+// NewUUID generates a random (version 4) UUID.
+func NewUUID() UUID {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return u
+}
+
+func (u UUID) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+func (u UUID) halves() (most, least int64) {
+	for i := 0; i < 8; i++ {
+		most = most<<8 | int64(u[i])
+		least = least<<8 | int64(u[i+8])
+	}
+	return
+}
+
+func uuidFromHalves(most, least int64) (u UUID) {
+	for i := 7; i >= 0; i-- {
+		u[i] = byte(most)
+		u[i+8] = byte(least)
+		most >>= 8
+		least >>= 8
+	}
+	return
+}
+
+// Entity is an entity (mob, item, vehicle, ...) in a chunk.
+//
+// The common tags are available as fields, the other tags can be accessed in NBT or with the subtype views (e.g. Mob).
+type Entity struct {
+	ID         string     // Type of the entity, e.g. "minecraft:zombie" ("Zombie" before Minecraft 1.11).
+	Pos        [3]float64 // Global position (X, Y, Z).
+	Motion     [3]float64 // Velocity in blocks per tick (X, Y, Z).
+	Rotation   [2]float32 // Yaw and pitch in degrees.
+	UUID       UUID
+	CustomName string // Name shown above the entity. Since Minecraft 1.13, this is a JSON text component. Empty, if not set.
+
+	// All tags of the entity. The tags of the fields above are updated from the fields, when the chunk is saved.
+	NBT nbt.TagCompound
+
+	noPos bool // The entity was read without a Pos tag, it stays in its chunk (see Chunk.Entities).
+}
+
+var (
+	InvalidEntity = errors.New("Entity has no id tag")
+)
+
+func getDoubleList(tc nbt.TagCompound, name string, vals []float64) error {
+	list, err := tc.GetList(name)
+	if err != nil {
+		return err
+	}
+	if list.Type != nbt.TAG_Double || len(list.Elems) != len(vals) {
+		return fmt.Errorf("%s is not a list of %d TAG_Double", name, len(vals))
+	}
+	for i, v := range list.Elems {
+		vals[i] = v.(float64)
+	}
+	return nil
+}
+
+func getFloatList(tc nbt.TagCompound, name string, vals []float32) error {
+	list, err := tc.GetList(name)
+	if err != nil {
+		return err
+	}
+	if list.Type != nbt.TAG_Float || len(list.Elems) != len(vals) {
+		return fmt.Errorf("%s is not a list of %d TAG_Float", name, len(vals))
+	}
+	for i, v := range list.Elems {
+		vals[i] = v.(float32)
+	}
+	return nil
+}
+
+// optional turns nbt.NotFound into nil.
+func optional(err error) error {
+	if err == nbt.NotFound {
+		return nil
+	}
+	return err
+}
+
+// EntityFromNBT creates an entity from its NBT data.
+func EntityFromNBT(tc nbt.TagCompound) (*Entity, error) {
+	e := &Entity{NBT: tc}
+
+	var err error
+	if e.ID, err = tc.GetString("id"); err != nil {
+		if err == nbt.NotFound {
+			return nil, InvalidEntity
+		}
+		return nil, fmt.Errorf("Could not read id tag: %s", err)
+	}
+
+	switch err := getDoubleList(tc, "Pos", e.Pos[:]); err {
+	case nil:
+	case nbt.NotFound:
+		e.noPos = true
+	default:
+		return nil, fmt.Errorf("Could not read Pos tag: %s", err)
+	}
+	if err := optional(getDoubleList(tc, "Motion", e.Motion[:])); err != nil {
+		return nil, fmt.Errorf("Could not read Motion tag: %s", err)
+	}
+	if err := optional(getFloatList(tc, "Rotation", e.Rotation[:])); err != nil {
+		return nil, fmt.Errorf("Could not read Rotation tag: %s", err)
+	}
+
+	// Since Minecraft 1.16 the UUID is stored as an int array, before as two longs.
+	if ints, err := tc.GetIntArray("UUID"); err == nil && len(ints) == 4 {
+		e.UUID = uuidFromHalves(int64(ints[0])<<32|int64(uint32(ints[1])), int64(ints[2])<<32|int64(uint32(ints[3])))
+	} else {
+		most, err1 := tc.GetLong("UUIDMost")
+		least, err2 := tc.GetLong("UUIDLeast")
+		if err1 == nil && err2 == nil {
+			e.UUID = uuidFromHalves(most, least)
+		}
+	}
+
+	if e.CustomName, err = tc.GetString("CustomName"); optional(err) != nil {
+		return nil, fmt.Errorf("Could not read CustomName tag: %s", err)
+	}
+
+	return e, nil
+}
+
+// toNBT creates the NBT data of the entity in the format of the given data version.
+// Entities without an ID (see Chunk.Entities) are kept as they are.
+func (e *Entity) toNBT(dataVersion int32) nbt.TagCompound {
+	tc := copyCompound(e.NBT)
+	if tc == nil {
+		tc = make(nbt.TagCompound)
+	}
+	if e.ID == "" {
+		return tc
+	}
+
+	tc["id"] = nbt.Tag{nbt.TAG_String, e.ID}
+	if !e.noPos || e.Pos != ([3]float64{}) {
+		tc["Pos"] = nbt.NewListTag(nbt.TAG_Double, e.Pos[:])
+	}
+	tc["Motion"] = nbt.NewListTag(nbt.TAG_Double, e.Motion[:])
+	tc["Rotation"] = nbt.NewListTag(nbt.TAG_Float, e.Rotation[:])
+
+	most, least := e.UUID.halves()
+	if dataVersion >= dataVersionIntArrayUUIDs {
+		delete(tc, "UUIDMost")
+		delete(tc, "UUIDLeast")
+		tc["UUID"] = nbt.NewIntArrayTag([]int32{int32(most >> 32), int32(most), int32(least >> 32), int32(least)})
+	} else {
+		tc["UUIDMost"] = nbt.NewLongTag(most)
+		tc["UUIDLeast"] = nbt.NewLongTag(least)
+	}
+
+	if e.CustomName != "" {
+		tc["CustomName"] = nbt.Tag{nbt.TAG_String, e.CustomName}
+	} else {
+		delete(tc, "CustomName")
+	}
+
+	return tc
+}
+
+// NewEntity creates an entity of the given type at the global position x, y, z with a random UUID.
+// The id should be namespaced (e.g. "minecraft:pig") since Minecraft 1.11.
+func NewEntity(id string, x, y, z float64) *Entity {
+	return &Entity{
+		ID:   id,
+		Pos:  [3]float64{x, y, z},
+		UUID: NewUUID(),
+		NBT: nbt.TagCompound{
+			"FallDistance":   nbt.NewFloatTag(0),
+			"Fire":           nbt.NewShortTag(-1),
+			"Air":            nbt.NewShortTag(300),
+			"OnGround":       nbt.NewByteTag(0),
+			"Invulnerable":   nbt.NewByteTag(0),
+			"PortalCooldown": nbt.NewIntTag(0),
+		},
+	}
+}
+
+// BlockPos returns the position of the block the entity is in.
+func (e *Entity) BlockPos() (x, y, z int) {
+	return floor(e.Pos[0]), floor(e.Pos[1]), floor(e.Pos[2])
+}
+
+func floor(f float64) int { return int(math.Floor(f)) }
+
+// Is checks, if the entity is of the given type. Namespaced and plain IDs are treated equally (e.g. "minecraft:pig" and "pig").
+func (e *Entity) Is(id string) bool {
+	return stripNamespace(e.ID) == stripNamespace(id)
+}
+
+func stripNamespace(id string) string {
+	if i := strings.IndexByte(id, ':'); i >= 0 {
+		return id[i+1:]
+	}
+	return id
+}
+
+// set sets a tag in the NBT data of the entity.
+func (e *Entity) set(name string, tag nbt.Tag) {
+	if e.NBT == nil {
+		e.NBT = make(nbt.TagCompound)
+	}
+	e.NBT[name] = tag
+}
+
+func (e *Entity) getByte(name string) byte {
+	v, _ := e.NBT.GetByte(name)
+	return v
+}
+
+func (e *Entity) setByte(name string, v byte)     { e.set(name, nbt.NewByteTag(v)) }
+func (e *Entity) setShort(name string, v int16)   { e.set(name, nbt.NewShortTag(v)) }
+func (e *Entity) setFloat(name string, v float32) { e.set(name, nbt.NewFloatTag(v)) }
+
+func (e *Entity) getFlag(name string) bool { return e.getByte(name) != 0 }
+
+func (e *Entity) setFlag(name string, v bool) {
+	b := byte(0)
+	if v {
+		b = 1
+	}
+	e.setByte(name, b)
+}
+
+func (e *Entity) getCompound(name string) nbt.TagCompound {
+	tc, _ := e.NBT.GetCompound(name)
+	return tc
+}
+
+// setCompound sets a compound tag. A nil compound removes the tag.
+func (e *Entity) setCompound(name string, tc nbt.TagCompound) {
+	if tc == nil {
+		delete(e.NBT, name)
+		return
+	}
+	e.set(name, nbt.Tag{nbt.TAG_Compound, tc})
+}
+
+func (e *Entity) getCompoundList(name string) []nbt.TagCompound {
+	list, err := e.NBT.GetList(name)
+	if err != nil || list.Type != nbt.TAG_Compound {
+		return nil
+	}
+	tcs := make([]nbt.TagCompound, len(list.Elems))
+	for i, elem := range list.Elems {
+		tcs[i] = elem.(nbt.TagCompound)
+	}
+	return tcs
+}
+
+func (e *Entity) setCompoundList(name string, tcs []nbt.TagCompound) {
+	e.set(name, nbt.NewListTag(nbt.TAG_Compound, tcs))
+}
+
+// tags returns the NBT data of the entity, creating it if needed.
+func (e *Entity) tags() nbt.TagCompound {
+	if e.NBT == nil {
+		e.NBT = make(nbt.TagCompound)
+	}
+	return e.NBT
+}
+
+// getItem returns the item stored in a compound tag (nil, if not set or invalid).
+func (e *Entity) getItem(name string) *ItemStack {
+	tc := e.getCompound(name)
+	if tc == nil {
+		return nil
+	}
+	it, _ := ItemStackFromNBT(tc)
+	return it
+}
+
+// setItem stores an item in a compound tag. A nil item removes the tag.
+func (e *Entity) setItem(name string, item *ItemStack) {
+	if item == nil {
+		e.setCompound(name, nil)
+		return
+	}
+	e.setCompound(name, item.toNBT())
+}
@@ -0,0 +1,153 @@
+package mcmap
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Maximum distance of blocks from the world origin.
+const worldBorder = 30000000
+
+// Box is an axis-aligned box in global coordinates. Min and Max are inclusive.
+type Box struct {
+	MinX, MinY, MinZ float64
+	MaxX, MaxY, MaxZ float64
+}
+
+// Contains checks, if the point x, y, z is inside the box.
+func (b Box) Contains(x, y, z float64) bool {
+	return x >= b.MinX && x <= b.MaxX && y >= b.MinY && y <= b.MaxY && z >= b.MinZ && z <= b.MaxZ
+}
+
+// EntityFilter selects entities. It returns true for matching entities.
+type EntityFilter func(*Entity) bool
+
+// EntitiesOfType returns a filter matching entities of the given types (see Entity.Is).
+func EntitiesOfType(ids ...string) EntityFilter {
+	return func(e *Entity) bool {
+		for _, id := range ids {
+			if e.Is(id) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// EntitiesNamed returns a filter matching entities with the given custom name (see Entity.CustomNameText).
+func EntitiesNamed(name string) EntityFilter {
+	return func(e *Entity) bool { return e.CustomName != "" && e.CustomNameText() == name }
+}
+
+// CustomNameText returns the text of the custom name. Since Minecraft 1.13 the custom name is a JSON text component,
+// in this case the plain text of it (without formatting) is returned.
+func (e *Entity) CustomNameText() string { return plainText(e.CustomName) }
+
+// plainText returns the text of a JSON text component without formatting.
+// If s is not a text component, it is returned unchanged (text before Minecraft 1.8 / 1.13).
+func plainText(s string) string {
+	var str string
+	if json.Unmarshal([]byte(s), &str) == nil {
+		return str
+	}
+
+	var component struct {
+		Text  string            `json:"text"`
+		Extra []json.RawMessage `json:"extra"`
+	}
+	if json.Unmarshal([]byte(s), &component) == nil {
+		text := component.Text
+		for _, extra := range component.Extra {
+			text += plainText(string(extra))
+		}
+		return text
+	}
+
+	var components []json.RawMessage
+	if json.Unmarshal([]byte(s), &components) == nil {
+		text := ""
+		for _, c := range components {
+			text += plainText(string(c))
+		}
+		return text
+	}
+
+	return s
+}
+
+// EntitiesIn returns all entities of the region within the box. Use infinite coordinates to search the whole region.
+//
+// Chunks that were not loaded before are unloaded again, so changes to their entities will not be saved.
+// Load the chunk of an entity with Region.Chunk to modify it.
+func (reg *Region) EntitiesIn(box Box) ([]*Entity, error) {
+	return reg.findEntities(box, nil)
+}
+
+// EntitiesNear returns the entities of the region within radius blocks around x, y, z, that match the filter
+// (filter can be nil to get all entities). See EntitiesIn for notes about modifying the entities.
+func (reg *Region) EntitiesNear(x, y, z, radius float64, filter EntityFilter) ([]*Entity, error) {
+	box := Box{x - radius, y - radius, z - radius, x + radius, y + radius, z + radius}
+	return reg.findEntities(box, func(e *Entity) bool {
+		dx, dy, dz := e.Pos[0]-x, e.Pos[1]-y, e.Pos[2]-z
+		if dx*dx+dy*dy+dz*dz > radius*radius {
+			return false
+		}
+		return filter == nil || filter(e)
+	})
+}
+
+// findEntities scans all available chunks intersecting the box.
+func (reg *Region) findEntities(box Box, filter EntityFilter) (found []*Entity, err error) {
+	// Clamp to the world border, so infinite boxes can be used.
+	toChunk := func(f float64) int { return int(math.Floor(math.Max(math.Min(f, worldBorder), -worldBorder))) >> 4 }
+	chunkRange := func(min, max float64) (int, int) { return toChunk(min), toChunk(max) }
+	cxMin, cxMax := chunkRange(box.MinX, box.MaxX)
+	czMin, czMax := chunkRange(box.MinZ, box.MaxZ)
+
+	for scPos := range reg.superchunksAvail {
+		scxMin, sczMin := superchunkToChunk(scPos.X, scPos.Z, 0, 0)
+		scxMax, sczMax := scxMin+superchunkSizeXZ-1, sczMin+superchunkSizeXZ-1
+
+		for cx := maxInt(cxMin, scxMin); cx <= minInt(cxMax, scxMax); cx++ {
+			for cz := maxInt(czMin, sczMin); cz <= minInt(czMax, sczMax); cz++ {
+				wasLoaded := reg.chunkLoaded(cx, cz)
+				c, err := reg.Chunk(cx, cz)
+				switch err {
+				case nil:
+				case NotAvailable:
+					continue
+				default:
+					return nil, err
+				}
+
+				for _, ent := range c.Entities {
+					if box.Contains(ent.Pos[0], ent.Pos[1], ent.Pos[2]) && (filter == nil || filter(ent)) {
+						found = append(found, ent)
+					}
+				}
+
+				if !wasLoaded {
+					if err := c.MarkUnused(); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,357 @@
+package mcmap
+
+import (
+	"errors"
+	"github.com/silvasur/gonbt/nbt"
+)
+
+// This file contains views of entities, giving typed access to the tags of some kinds of entities.
+// The views work on the NBT data of the entity, so changes are saved together with the entity.
+// The constructors use the namespaced entity IDs of Minecraft 1.11 and later.
+
+func emptyCompounds(n int) []nbt.TagCompound {
+	tcs := make([]nbt.TagCompound, n)
+	for i := range tcs {
+		tcs[i] = make(nbt.TagCompound)
+	}
+	return tcs
+}
+
+func floatList(n int, v float32) nbt.Tag {
+	vals := make([]float32, n)
+	for i := range vals {
+		vals[i] = v
+	}
+	return nbt.NewListTag(nbt.TAG_Float, vals)
+}
+
+// Mob is a view of a living entity (mobs, players and armor stands).
+type Mob struct{ *Entity }
+
+// AsMob returns the mob view of the entity.
+func (e *Entity) AsMob() Mob { return Mob{e} }
+
+// NewMob creates a mob of the given type (e.g. "minecraft:zombie") at the global position x, y, z without any equipment.
+func NewMob(id string, x, y, z float64) *Entity {
+	e := NewEntity(id, x, y, z)
+	e.setCompoundList("HandItems", emptyCompounds(2))
+	e.setCompoundList("ArmorItems", emptyCompounds(4))
+	e.NBT["HandDropChances"] = floatList(2, 0.085)
+	e.NBT["ArmorDropChances"] = floatList(4, 0.085)
+	e.NBT["CanPickUpLoot"] = nbt.NewByteTag(0)
+	e.NBT["PersistenceRequired"] = nbt.NewByteTag(0)
+	return e
+}
+
+// Health returns the health points of the mob (0, if not set; Minecraft uses the maximum health in that case).
+func (m Mob) Health() float32 {
+	health, _ := m.NBT.GetFloat("Health")
+	return health
+}
+
+func (m Mob) SetHealth(health float32) {
+	m.setFloat("Health", health)
+}
+
+// NoAI checks, if the AI of the mob is disabled.
+func (m Mob) NoAI() bool     { return m.getFlag("NoAI") }
+func (m Mob) SetNoAI(b bool) { m.setFlag("NoAI", b) }
+
+// PersistenceRequired checks, if the mob never despawns.
+func (m Mob) PersistenceRequired() bool     { return m.getFlag("PersistenceRequired") }
+func (m Mob) SetPersistenceRequired(b bool) { m.setFlag("PersistenceRequired", b) }
+
+// HandItems returns the items in the main hand (slot 0) and the off hand (slot 1) (Minecraft 1.9+).
+func (m Mob) HandItems() Inventory { return listInventory{m.tags(), "HandItems", 2} }
+
+// ArmorItems returns the worn armor, from the feet (slot 0) to the head (slot 3) (Minecraft 1.9+).
+func (m Mob) ArmorItems() Inventory { return listInventory{m.tags(), "ArmorItems", 4} }
+
+// ItemEntity is a view of a dropped item.
+type ItemEntity struct{ *Entity }
+
+// AsItem returns the item view of the entity.
+func (e *Entity) AsItem() ItemEntity { return ItemEntity{e} }
+
+// NewItemEntity creates a dropped item at the global position x, y, z.
+func NewItemEntity(item *ItemStack, x, y, z float64) *Entity {
+	e := NewEntity("minecraft:item", x, y, z)
+	ItemEntity{e}.SetItem(item)
+	e.NBT["Age"] = nbt.NewShortTag(0)
+	e.NBT["Health"] = nbt.NewShortTag(5)
+	e.NBT["PickupDelay"] = nbt.NewShortTag(10)
+	return e
+}
+
+func (it ItemEntity) Item() *ItemStack        { return it.getItem("Item") }
+func (it ItemEntity) SetItem(item *ItemStack) { it.setItem("Item", item) }
+
+// Age returns the number of ticks the item exists. Items despawn at an age of 6000, an age of -32768 prevents despawning.
+func (it ItemEntity) Age() int16 {
+	age, _ := it.NBT.GetShort("Age")
+	return age
+}
+
+func (it ItemEntity) SetAge(age int16) { it.setShort("Age", age) }
+
+// PickupDelay returns the number of ticks until the item can be picked up.
+func (it ItemEntity) PickupDelay() int16 {
+	delay, _ := it.NBT.GetShort("PickupDelay")
+	return delay
+}
+
+func (it ItemEntity) SetPickupDelay(delay int16) { it.setShort("PickupDelay", delay) }
+
+// MinecartKind is the kind of a minecart.
+type MinecartKind int
+
+const (
+	MinecartUnknown MinecartKind = iota
+	MinecartRideable
+	MinecartChest
+	MinecartFurnace
+	MinecartTNT
+	MinecartSpawner
+	MinecartHopper
+	MinecartCommandBlock
+)
+
+// Entity IDs of the minecarts. Since Minecraft 1.11 / since Minecraft 1.8.
+var minecartIDs = map[MinecartKind][2]string{
+	MinecartRideable:     {"minecraft:minecart", "MinecartRideable"},
+	MinecartChest:        {"minecraft:chest_minecart", "MinecartChest"},
+	MinecartFurnace:      {"minecraft:furnace_minecart", "MinecartFurnace"},
+	MinecartTNT:          {"minecraft:tnt_minecart", "MinecartTNT"},
+	MinecartSpawner:      {"minecraft:spawner_minecart", "MinecartSpawner"},
+	MinecartHopper:       {"minecraft:hopper_minecart", "MinecartHopper"},
+	MinecartCommandBlock: {"minecraft:command_block_minecart", "MinecartCommandBlock"},
+}
+
+// Minecart is a view of a minecart.
+type Minecart struct{ *Entity }
+
+// AsMinecart returns the minecart view of the entity.
+func (e *Entity) AsMinecart() Minecart { return Minecart{e} }
+
+// NewMinecart creates an empty minecart of the given kind at the global position x, y, z.
+func NewMinecart(kind MinecartKind, x, y, z float64) *Entity {
+	e := NewEntity(minecartIDs[kind][0], x, y, z)
+	if kind == MinecartChest || kind == MinecartHopper {
+		e.setCompoundList("Items", nil)
+	}
+	return e
+}
+
+// Kind returns the kind of the minecart or MinecartUnknown, if the entity is not a minecart.
+func (m Minecart) Kind() MinecartKind {
+	if m.ID == "Minecart" {
+		// Before Minecraft 1.8 all minecarts had the same ID.
+		switch t, _ := m.NBT.GetInt("Type"); t {
+		case 0:
+			return MinecartRideable
+		case 1:
+			return MinecartChest
+		case 2:
+			return MinecartFurnace
+		}
+		return MinecartUnknown
+	}
+
+	for kind, ids := range minecartIDs {
+		if m.ID == ids[0] || m.ID == ids[1] || m.ID == stripNamespace(ids[0]) {
+			return kind
+		}
+	}
+	return MinecartUnknown
+}
+
+// Inventory returns the inventory of chest and hopper minecarts.
+func (m Minecart) Inventory() Inventory {
+	size := 0
+	switch m.Kind() {
+	case MinecartChest:
+		size = 27
+	case MinecartHopper:
+		size = 5
+	}
+	return slotInventory{m.tags(), "Items", size}
+}
+
+// DisplayOffset returns the offset (in pixels) of the block displayed in the minecart.
+func (m Minecart) DisplayOffset() int32 {
+	off, _ := m.NBT.GetInt("DisplayOffset")
+	return off
+}
+
+// SetDisplayOffset sets the offset of the displayed block. This also enables the custom display of the block.
+func (m Minecart) SetDisplayOffset(off int32) {
+	m.set("DisplayOffset", nbt.NewIntTag(off))
+	m.setFlag("CustomDisplayTile", true)
+}
+
+// ItemFrame is a view of an item frame.
+type ItemFrame struct{ *Entity }
+
+// AsItemFrame returns the item frame view of the entity.
+func (e *Entity) AsItemFrame() ItemFrame { return ItemFrame{e} }
+
+// NewItemFrame creates an item frame hanging at the block x, y, z (global coordinates) with the given facing
+// (Minecraft 1.13+: 0 = down, 1 = up, 2 = north, 3 = south, 4 = west, 5 = east). item can be nil.
+func NewItemFrame(x, y, z int, facing byte, item *ItemStack) *Entity {
+	e := NewEntity("minecraft:item_frame", float64(x)+0.5, float64(y)+0.5, float64(z)+0.5)
+	f := ItemFrame{e}
+	f.SetHangingPos(x, y, z)
+	f.SetFacing(facing)
+	f.SetItem(item)
+	e.NBT["ItemRotation"] = nbt.NewByteTag(0)
+	e.NBT["ItemDropChance"] = nbt.NewFloatTag(1)
+	return e
+}
+
+// Item returns the item in the frame (nil, if the frame is empty).
+func (f ItemFrame) Item() *ItemStack        { return f.getItem("Item") }
+func (f ItemFrame) SetItem(item *ItemStack) { f.setItem("Item", item) }
+
+// ItemRotation returns the rotation of the item in steps of 45 degrees.
+func (f ItemFrame) ItemRotation() byte     { return f.getByte("ItemRotation") }
+func (f ItemFrame) SetItemRotation(r byte) { f.setByte("ItemRotation", r) }
+
+// Facing returns the direction the frame is facing (see NewItemFrame; before Minecraft 1.13: 0 = south, 1 = west, 2 = north, 3 = east).
+func (f ItemFrame) Facing() byte          { return f.getByte("Facing") }
+func (f ItemFrame) SetFacing(facing byte) { f.setByte("Facing", facing) }
+
+// HangingPos returns the global coordinates of the block the frame is in.
+func (f ItemFrame) HangingPos() (x, y, z int) {
+	_x, _ := f.NBT.GetInt("TileX")
+	_y, _ := f.NBT.GetInt("TileY")
+	_z, _ := f.NBT.GetInt("TileZ")
+	return int(_x), int(_y), int(_z)
+}
+
+func (f ItemFrame) SetHangingPos(x, y, z int) {
+	f.set("TileX", nbt.NewIntTag(int32(x)))
+	f.set("TileY", nbt.NewIntTag(int32(y)))
+	f.set("TileZ", nbt.NewIntTag(int32(z)))
+}
+
+// Fixed checks, if the frame can not be removed and the item can not be changed (Minecraft 1.16+).
+func (f ItemFrame) Fixed() bool     { return f.getFlag("Fixed") }
+func (f ItemFrame) SetFixed(b bool) { f.setFlag("Fixed", b) }
+
+// Invisible checks, if the frame itself is invisible (Minecraft 1.16+).
+func (f ItemFrame) Invisible() bool     { return f.getFlag("Invisible") }
+func (f ItemFrame) SetInvisible(b bool) { f.setFlag("Invisible", b) }
+
+// ArmorStand is a view of an armor stand.
+type ArmorStand struct{ Mob }
+
+// AsArmorStand returns the armor stand view of the entity.
+func (e *Entity) AsArmorStand() ArmorStand { return ArmorStand{Mob{e}} }
+
+// NewArmorStand creates an armor stand without equipment at the global position x, y, z.
+func NewArmorStand(x, y, z float64) *Entity {
+	e := NewEntity("minecraft:armor_stand", x, y, z)
+	e.setCompoundList("HandItems", emptyCompounds(2))
+	e.setCompoundList("ArmorItems", emptyCompounds(4))
+	e.setCompound("Pose", make(nbt.TagCompound))
+	return e
+}
+
+func (a ArmorStand) Invisible() bool       { return a.getFlag("Invisible") }
+func (a ArmorStand) SetInvisible(b bool)   { a.setFlag("Invisible", b) }
+func (a ArmorStand) NoBasePlate() bool     { return a.getFlag("NoBasePlate") }
+func (a ArmorStand) SetNoBasePlate(b bool) { a.setFlag("NoBasePlate", b) }
+func (a ArmorStand) ShowArms() bool        { return a.getFlag("ShowArms") }
+func (a ArmorStand) SetShowArms(b bool)    { a.setFlag("ShowArms", b) }
+func (a ArmorStand) Small() bool           { return a.getFlag("Small") }
+func (a ArmorStand) SetSmall(b bool)       { a.setFlag("Small", b) }
+func (a ArmorStand) Marker() bool          { return a.getFlag("Marker") }
+func (a ArmorStand) SetMarker(b bool)      { a.setFlag("Marker", b) }
+
+// Pose returns the rotations of the body parts (Head, Body, LeftArm, RightArm, LeftLeg, RightLeg: lists of 3 floats in degrees).
+func (a ArmorStand) Pose() nbt.TagCompound        { return a.getCompound("Pose") }
+func (a ArmorStand) SetPose(pose nbt.TagCompound) { a.setCompound("Pose", pose) }
+
+// Trade is a trade offered by a villager or wandering trader.
+type Trade struct {
+	Buy, BuyB *ItemStack // Items the player pays. BuyB is nil, if the trade has only one price item.
+	Sell      *ItemStack
+	Uses      int32
+	MaxUses   int32
+
+	// All tags of the trade. The tags of the fields above are updated from the fields.
+	NBT nbt.TagCompound
+}
+
+var (
+	InvalidTrade = errors.New("Trade has no Buy or Sell item")
+)
+
+// Villager is a view of a villager or wandering trader.
+type Villager struct{ Mob }
+
+// AsVillager returns the villager view of the entity.
+func (e *Entity) AsVillager() Villager { return Villager{Mob{e}} }
+
+// Trades returns the trades offered by the villager. Invalid trades are skipped.
+func (v Villager) Trades() []Trade {
+	var trades []Trade
+	for _, tc := range tileEntity(v.getCompound("Offers")).getCompoundList("Recipes") {
+		t := Trade{NBT: tc}
+		t.Buy = v.tradeItem(tc, "buy")
+		t.BuyB = v.tradeItem(tc, "buyB")
+		t.Sell = v.tradeItem(tc, "sell")
+		t.Uses, _ = tc.GetInt("uses")
+		t.MaxUses, _ = tc.GetInt("maxUses")
+		if t.Buy != nil && t.Sell != nil {
+			trades = append(trades, t)
+		}
+	}
+	return trades
+}
+
+func (v Villager) tradeItem(tc nbt.TagCompound, name string) *ItemStack {
+	item, err := tc.GetCompound(name)
+	if err != nil {
+		return nil
+	}
+	it, err := ItemStackFromNBT(item)
+	if err != nil || (it.ID == "minecraft:air" && it.Count == 0) {
+		return nil
+	}
+	return it
+}
+
+// SetTrades replaces the trades offered by the villager. Every trade needs a Buy and a Sell item, otherwise
+// InvalidTrade is returned and the trades are not changed.
+func (v Villager) SetTrades(trades []Trade) error {
+	recipes := make([]nbt.TagCompound, len(trades))
+	for i, t := range trades {
+		if t.Buy == nil || t.Sell == nil {
+			return InvalidTrade
+		}
+
+		tc := copyCompound(t.NBT)
+		if tc == nil {
+			tc = nbt.TagCompound{"rewardExp": nbt.NewByteTag(1)}
+		}
+		tc["buy"] = nbt.Tag{nbt.TAG_Compound, t.Buy.toNBT()}
+		if t.BuyB != nil {
+			tc["buyB"] = nbt.Tag{nbt.TAG_Compound, t.BuyB.toNBT()}
+		} else {
+			delete(tc, "buyB")
+		}
+		tc["sell"] = nbt.Tag{nbt.TAG_Compound, t.Sell.toNBT()}
+		tc["uses"] = nbt.NewIntTag(t.Uses)
+		tc["maxUses"] = nbt.NewIntTag(t.MaxUses)
+		recipes[i] = tc
+	}
+
+	offers := v.getCompound("Offers")
+	if offers == nil {
+		offers = make(nbt.TagCompound)
+		v.setCompound("Offers", offers)
+	}
+	tileEntity(offers).setCompoundList("Recipes", recipes)
+	return nil
+}
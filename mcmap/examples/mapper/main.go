@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/silvasur/gomcmap/mcmap"
 	"image"
+	"image/color"
 	"image/png"
 	"os"
 )
@@ -48,15 +49,15 @@ chunkLoop:
 		scanZ:
 			for z := 0; z < mcmap.ChunkSizeXZ; z++ {
 				ax, az := mcmap.ChunkToBlock(cx, cz, x, z)
-				for y := mcmap.ChunkSizeY; y >= 0; y-- {
+				for y := chunk.MaxY(); y >= chunk.MinY(); y-- {
 					blk := chunk.Block(x, y, z)
-					c, ok := colors[blk.ID]
-					if ok {
+					c := blk.ID.Properties().MapColor
+					if c.A != 0 {
 						img.Set(ax-(xmin*mcmap.ChunkSizeXZ), az-(zmin*mcmap.ChunkSizeXZ), c)
 						continue scanZ
 					}
 				}
-				img.Set(ax-(xmin*mcmap.ChunkSizeXZ), az-(zmin*mcmap.ChunkSizeXZ), rgb(0x000000))
+				img.Set(ax-(xmin*mcmap.ChunkSizeXZ), az-(zmin*mcmap.ChunkSizeXZ), color.Black)
 			}
 		}
 
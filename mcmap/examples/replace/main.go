@@ -23,37 +23,29 @@ func main() {
 		os.Exit(1)
 	}
 
-chunkLoop:
+	// Select all chunks of the region.
+	first := true
+	var sel mcmap.Cuboid
 	for chunkPos := range region.AllChunks() {
-		cx, cz := chunkPos.X, chunkPos.Z
-		chunk, err := region.Chunk(cx, cz)
-		switch err {
-		case nil:
-		case mcmap.NotAvailable:
-			continue chunkLoop
-		default:
-			fmt.Fprintf(os.Stderr, "Error while getting chunk (%d, %d): %s\n", cx, cz, err)
-			os.Exit(1)
-		}
-
-		modified := false
-		chunk.Iter(func(x, y, z int, blk *mcmap.Block) {
-			if blk.ID == mcmap.BlkBlockOfIron {
-				blk.ID = mcmap.BlkBlockOfDiamond
-				modified = true
-			}
-		})
-
-		if modified {
-			fmt.Printf("Modified chunk %d, %d.\n", cx, cz)
-			chunk.MarkModified()
+		x, z := mcmap.ChunkToBlock(chunkPos.X, chunkPos.Z, 0, 0)
+		chunkSel := mcmap.Cuboid{x, -2048, z, x + mcmap.ChunkSizeXZ - 1, 2047, z + mcmap.ChunkSizeXZ - 1}
+		if first {
+			sel, first = chunkSel, false
+		} else {
+			sel = mcmap.Union{sel, chunkSel}.Bounds()
 		}
+	}
+	if first {
+		fmt.Println("The region contains no chunks.")
+		return
+	}
 
-		if err := chunk.MarkUnused(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error while unloading chunk %d, %d: %s\n", cx, cz, err)
-			os.Exit(1)
-		}
+	n, err := region.Replace(sel, mcmap.Block{ID: mcmap.BlkBlockOfIron}, mcmap.Block{ID: mcmap.BlkBlockOfDiamond})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error while replacing blocks: %s\n", err)
+		os.Exit(1)
 	}
+	fmt.Printf("Replaced %d blocks.\n", n)
 
 	if err := region.Save(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error while saving: %s\n", err)
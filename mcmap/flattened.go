@@ -0,0 +1,554 @@
+package mcmap
+
+import (
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+)
+
+// This file implements the chunk format used since Minecraft 1.13, where blocks are stored as
+// indices into a palette of block states.
+
+// Tags of the Level compound (the root compound since 1.18) that are interpreted by readFlattenedTags.
+// All other tags are kept as they are.
+var flattenedLevelTags = map[string]bool{
+	"DataVersion":    true,
+	"xPos":           true,
+	"yPos":           true,
+	"zPos":           true,
+	"LastUpdate":     true,
+	"InhabitedTime":  true,
+	"Status":         true,
+	"Heightmaps":     true,
+	"Entities":       true,
+	"TileEntities":   true,
+	"TileTicks":      true,
+	"LiquidTicks":    true,
+	"Sections":       true,
+	"block_entities": true,
+	"block_ticks":    true,
+	"fluid_ticks":    true,
+	"sections":       true,
+}
+
+// Tags of the sections that are interpreted by readFlattenedSection.
+var flattenedSectionTags = map[string]bool{
+	"Y":            true,
+	"BlockLight":   true,
+	"SkyLight":     true,
+	"Palette":      true,
+	"BlockStates":  true,
+	"block_states": true,
+	"biomes":       true,
+}
+
+// Flags for the light arrays missing in a section (see Chunk.missingLight).
+const (
+	noBlockLight = 1 << iota
+	noSkyLight
+)
+
+// Chunk statuses of chunks, where the terrain was populated (decorated).
+var populatedStatuses = map[string]bool{
+	"decorated":        true,
+	"lighted":          true,
+	"mobs_spawned":     true,
+	"finalized":        true,
+	"fullchunk":        true,
+	"postprocessed":    true,
+	"features":         true,
+	"initialize_light": true,
+	"light":            true,
+	"spawn":            true,
+	"heightmaps":       true,
+	"full":             true,
+}
+
+func getLongArray(tc nbt.TagCompound, name string) ([]int64, error) {
+	tag, ok := tc[name]
+	if !ok {
+		return nil, nbt.NotFound
+	}
+	if tag.Type != nbt.TAG_Long_Array {
+		return nil, fmt.Errorf("%s is not a TAG_Long_Array", name)
+	}
+	return tag.Payload.([]int64), nil
+}
+
+// bitsForPalette calculates the number of bits used to store an index into a palette of size n (but at least min bits).
+func bitsForPalette(n int, min uint) uint {
+	bits := uint(0)
+	for (1 << bits) < n {
+		bits++
+	}
+	if bits < min {
+		return min
+	}
+	return bits
+}
+
+// unpackIndices unpacks n values of the given bit width. If spanning is true, values can span two longs
+// (the format used before Minecraft 1.16).
+func unpackIndices(packed []int64, bits uint, n int, spanning bool) ([]int, error) {
+	vals := make([]int, n)
+	mask := uint64(1)<<bits - 1
+	perLong := int(64 / bits)
+
+	for i := range vals {
+		var v uint64
+		if spanning {
+			bitOff := uint(i) * bits
+			li, sh := int(bitOff/64), bitOff%64
+			if li >= len(packed) {
+				return nil, fmt.Errorf("Packed array too short (%d longs)", len(packed))
+			}
+			v = uint64(packed[li]) >> sh
+			if sh+bits > 64 {
+				if li+1 >= len(packed) {
+					return nil, fmt.Errorf("Packed array too short (%d longs)", len(packed))
+				}
+				v |= uint64(packed[li+1]) << (64 - sh)
+			}
+		} else {
+			li := i / perLong
+			if li >= len(packed) {
+				return nil, fmt.Errorf("Packed array too short (%d longs)", len(packed))
+			}
+			v = uint64(packed[li]) >> (uint(i%perLong) * bits)
+		}
+		vals[i] = int(v & mask)
+	}
+
+	return vals, nil
+}
+
+// packIndices is the reverse of unpackIndices.
+func packIndices(vals []int, bits uint, spanning bool) []int64 {
+	var packed []uint64
+	perLong := int(64 / bits)
+	if spanning {
+		packed = make([]uint64, (len(vals)*int(bits)+63)/64)
+	} else {
+		packed = make([]uint64, (len(vals)+perLong-1)/perLong)
+	}
+
+	for i, val := range vals {
+		v := uint64(val)
+		if spanning {
+			bitOff := uint(i) * bits
+			li, sh := int(bitOff/64), bitOff%64
+			packed[li] |= v << sh
+			if sh+bits > 64 {
+				packed[li+1] |= v >> (64 - sh)
+			}
+		} else {
+			packed[i/perLong] |= v << (uint(i%perLong) * bits)
+		}
+	}
+
+	rv := make([]int64, len(packed))
+	for i, v := range packed {
+		rv[i] = int64(v)
+	}
+	return rv
+}
+
+func (c *Chunk) readFlattenedTags(lvl nbt.TagCompound) error {
+	status, err := lvl.GetString("Status")
+	switch err {
+	case nil:
+	case nbt.NotFound:
+		status = "full"
+	default:
+		return fmt.Errorf("Could not read Status tag: %s", err)
+	}
+	c.status = status
+	c.populated = ChunkStatus(status).Populated()
+
+	sections, err := lvl.GetList(c.tagName("Sections"))
+	switch err {
+	case nil:
+	case nbt.NotFound:
+		sections = nbt.TagList{Type: nbt.TAG_End}
+	default:
+		return fmt.Errorf("Could not read %s tag: %s", c.tagName("Sections"), err)
+	}
+
+	if c.dataVersion >= dataVersionNoLevel {
+		minSection, numSections, err := sectionRange118(lvl, sections)
+		if err != nil {
+			return err
+		}
+		c.setRange(minSection*16, numSections*16)
+	} else {
+		c.setRange(0, ChunkSizeY)
+	}
+
+	// Sections not stored in the chunk have no light data, readFlattenedSection removes the flags of the arrays it finds.
+	c.missingLight = make(map[int]byte)
+	for y := c.minY / 16; y < (c.minY+c.sizeY)/16; y++ {
+		c.missingLight[y] = noBlockLight | noSkyLight
+	}
+
+	// Since 1.18, biomes are stored in the sections. Before, they are stored as one ID per column (ZX order)
+	// or, since 1.15, per cell of 4x4x4 blocks (YZX order).
+	c.initBiomes()
+	if biomes, err := lvl.GetIntArray("Biomes"); err == nil && len(biomes) == len(c.biomes) && c.dataVersion < dataVersionNoLevel {
+		for i, bio := range biomes {
+			if bio >= 0 && bio < firstDynamicBiome {
+				c.biomes[i] = Biome(bio)
+			}
+		}
+		delete(c.extra, "Biomes")
+	}
+
+	if sections.Type == nbt.TAG_Compound {
+		for _, _section := range sections.Elems {
+			section := _section.(nbt.TagCompound)
+			if err := c.readFlattenedSection(section); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sectionRange118 determines the vertical range (in sections) of a 1.18+ chunk. Sections with block states
+// are within the range, sections outside of the range only contain light data.
+func sectionRange118(root nbt.TagCompound, sections nbt.TagList) (minSection, numSections int, err error) {
+	minSection, maxSection := 0, -1
+	if sections.Type == nbt.TAG_Compound {
+		for _, _section := range sections.Elems {
+			section := _section.(nbt.TagCompound)
+			if _, ok := section["block_states"]; !ok {
+				continue
+			}
+			_y, err := section.GetByte("Y")
+			if err != nil {
+				return 0, 0, fmt.Errorf("Could not read sections -> Y tag: %s", err)
+			}
+			y := int(int8(_y))
+			if maxSection < minSection {
+				minSection, maxSection = y, y
+			} else if y < minSection {
+				minSection = y
+			} else if y > maxSection {
+				maxSection = y
+			}
+		}
+	}
+
+	yPos, err := root.GetInt("yPos")
+	switch err {
+	case nil:
+		if maxSection < minSection {
+			maxSection = int(yPos) + ChunkSizeY/16 - 1
+		}
+		minSection = int(yPos)
+	case nbt.NotFound:
+		if maxSection < minSection {
+			minSection, maxSection = 0, ChunkSizeY/16-1
+		}
+	default:
+		return 0, 0, fmt.Errorf("Could not read yPos tag: %s", err)
+	}
+
+	return minSection, maxSection - minSection + 1, nil
+}
+
+func (c *Chunk) readFlattenedSection(section nbt.TagCompound) error {
+	_y, err := section.GetByte("Y")
+	if err != nil {
+		return fmt.Errorf("Could not read Section -> Y tag: %s", err)
+	}
+	y := int(int8(_y))
+	if y*16 < c.minY || y*16 >= c.minY+c.sizeY {
+		// Sections outside of the world only contain light data.
+		c.extraSections = append(c.extraSections, section)
+		return nil
+	}
+	off := (y*16 - c.minY) * ChunkRectXZ
+	c.setSectionExtra(y, section, flattenedSectionTags)
+
+	if c.dataVersion >= dataVersionNoLevel {
+		biomes, err := section.GetCompound("biomes")
+		switch err {
+		case nil:
+			if err := c.readSectionBiomes(biomes, (y*16-c.minY)/biomeCellSize*biomeCellsXZ); err != nil {
+				return err
+			}
+		case nbt.NotFound:
+		default:
+			return fmt.Errorf("Could not read sections -> biomes tag: %s", err)
+		}
+	}
+
+	blockLight, err := section.GetByteArray("BlockLight")
+	if err != nil && err != nbt.NotFound {
+		return fmt.Errorf("Could not read Section -> BlockLight tag: %s", err)
+	}
+	skyLight, err := section.GetByteArray("SkyLight")
+	if err != nil && err != nbt.NotFound {
+		return fmt.Errorf("Could not read Section -> SkyLight tag: %s", err)
+	}
+
+	// Blocks without light data keep the defaults (no block light, full sky light).
+	if len(blockLight) == chunkSectionSize/2 {
+		c.missingLight[y] &^= noBlockLight
+		for i := 0; i < chunkSectionSize; i++ {
+			c.blocks[off+i].BlockLight = halfbyte(blockLight, i)
+		}
+	}
+	if len(skyLight) == chunkSectionSize/2 {
+		c.missingLight[y] &^= noSkyLight
+		for i := 0; i < chunkSectionSize; i++ {
+			c.blocks[off+i].SkyLight = halfbyte(skyLight, i)
+		}
+	}
+
+	// Since 1.18, palette and indices are stored in the block_states compound.
+	states, paletteName, dataName := section, "Palette", "BlockStates"
+	if c.dataVersion >= dataVersionNoLevel {
+		paletteName, dataName = "palette", "data"
+		states, err = section.GetCompound("block_states")
+		switch err {
+		case nil:
+		case nbt.NotFound:
+			return nil // Section contains only air.
+		default:
+			return fmt.Errorf("Could not read sections -> block_states tag: %s", err)
+		}
+	}
+
+	paletteList, err := states.GetList(paletteName)
+	switch err {
+	case nil:
+	case nbt.NotFound:
+		return nil // Section contains only air.
+	default:
+		return fmt.Errorf("Could not read Section -> %s tag: %s", paletteName, err)
+	}
+	if paletteList.Type != nbt.TAG_Compound || len(paletteList.Elems) == 0 {
+		return nil
+	}
+
+	type paletteEntry struct {
+		state *BlockState
+		id    BlockID
+		data  byte
+	}
+	palette := make([]paletteEntry, len(paletteList.Elems))
+	for i, _entry := range paletteList.Elems {
+		bs, err := blockStateFromNBT(_entry.(nbt.TagCompound))
+		if err != nil {
+			return fmt.Errorf("Could not read Section -> Palette: %s", err)
+		}
+		id, data := bs.Legacy()
+		palette[i] = paletteEntry{bs, id, data}
+	}
+
+	var indices []int
+	packed, err := getLongArray(states, dataName)
+	switch {
+	case err == nil:
+		bits := bitsForPalette(len(palette), 4)
+		indices, err = unpackIndices(packed, bits, chunkSectionSize, c.dataVersion < dataVersionNonSpanningStates)
+		if err != nil {
+			return fmt.Errorf("Could not read Section -> %s tag: %s", dataName, err)
+		}
+	case err == nbt.NotFound && len(palette) == 1:
+		// The whole section consists of the only block of the palette.
+		indices = make([]int, chunkSectionSize)
+	default:
+		return fmt.Errorf("Could not read Section -> %s tag: %s", dataName, err)
+	}
+
+	for i, idx := range indices {
+		if idx >= len(palette) {
+			return fmt.Errorf("Invalid palette index %d in Section -> BlockStates", idx)
+		}
+		entry := palette[idx]
+		blk := &c.blocks[off+i]
+		blk.ID = entry.id
+		blk.Data = entry.data
+		blk.State = entry.state
+	}
+
+	return nil
+}
+
+func (c *Chunk) writeFlattenedTags(lvl nbt.TagCompound) {
+	lvl["Status"] = nbt.Tag{nbt.TAG_String, c.status}
+	lvl["Heightmaps"] = nbt.Tag{nbt.TAG_Compound, c.heightMapsTag()}
+
+	if c.dataVersion < dataVersionNoLevel {
+		// Since 1.18, biomes are stored in the sections.
+		hasBiomes := false
+		biomes := make([]int32, len(c.biomes))
+		for i, bio := range c.biomes {
+			if bio != BioUncalculated {
+				hasBiomes = true
+			}
+			biomes[i] = int32(bio.numericBiome(BioPlains))
+		}
+		if hasBiomes {
+			lvl["Biomes"] = nbt.NewIntArrayTag(biomes)
+		}
+	}
+
+	sections := append([]nbt.TagCompound(nil), c.extraSections...)
+	states := make(map[blockKey]*BlockState) // Caches the results of Block.BlockState
+
+	for subchunk := 0; subchunk < c.sizeY/16; subchunk++ {
+		off := subchunk * chunkSectionSize
+		y := c.minY/16 + subchunk
+
+		blockLight := make([]byte, chunkSectionSize/2)
+		skyLight := make([]byte, chunkSectionSize/2)
+
+		paletteIndices := make(map[string]int)
+		palette := make([]nbt.TagCompound, 0)
+		indices := make([]int, chunkSectionSize)
+
+		// Sections containing only air and default light (no block light, full sky light) can be omitted.
+		omittable, defaultBlockLight, defaultSkyLight := true, true, true
+		for i := 0; i < chunkSectionSize; i++ {
+			blk := c.blocks[i+off]
+			if blk.ID != BlkAir {
+				omittable = false
+			}
+			if blk.BlockLight != 0 {
+				omittable, defaultBlockLight = false, false
+			}
+			if blk.SkyLight != maxLight {
+				omittable, defaultSkyLight = false, false
+			}
+
+			var bs *BlockState
+			if blk.State != nil {
+				// The state is only valid for the ID and data value it was set with (see Block.BlockState).
+				key := stateKey(blk)
+				var ok bool
+				if bs, ok = states[key]; !ok {
+					bs = blk.BlockState()
+					states[key] = bs
+				}
+			} else {
+				bs = blk.BlockState()
+			}
+
+			key := bs.String()
+			idx, ok := paletteIndices[key]
+			if !ok {
+				idx = len(palette)
+				paletteIndices[key] = idx
+				palette = append(palette, bs.toNBT())
+			}
+			indices[i] = idx
+
+			setHalfbyte(blockLight, i, blk.BlockLight)
+			setHalfbyte(skyLight, i, blk.SkyLight)
+		}
+
+		// Light arrays missing when the chunk was read are only written, if the light was changed.
+		section := c.newSection(y)
+		if c.missingLight[y]&noBlockLight == 0 || !defaultBlockLight {
+			section["BlockLight"] = nbt.NewByteArrayTag(blockLight)
+		}
+		if c.missingLight[y]&noSkyLight == 0 || !defaultSkyLight {
+			section["SkyLight"] = nbt.NewByteArrayTag(skyLight)
+		}
+
+		bits := bitsForPalette(len(palette), 4)
+		if c.dataVersion >= dataVersionNoLevel {
+			// 1.18+ chunks contain all sections, since the biomes are stored in them.
+			states := nbt.TagCompound{"palette": nbt.NewListTag(nbt.TAG_Compound, palette)}
+			if len(palette) > 1 {
+				states["data"] = nbt.Tag{nbt.TAG_Long_Array, packIndices(indices, bits, false)}
+			}
+			section["block_states"] = nbt.Tag{nbt.TAG_Compound, states}
+
+			section["biomes"] = nbt.Tag{nbt.TAG_Compound, c.sectionBiomesTag(subchunk * biomeCellsInSection)}
+		} else {
+			if omittable && len(c.sectionExtra[y]) == 0 {
+				continue
+			}
+
+			section["Palette"] = nbt.NewListTag(nbt.TAG_Compound, palette)
+			section["BlockStates"] = nbt.Tag{nbt.TAG_Long_Array, packIndices(indices, bits, c.dataVersion < dataVersionNonSpanningStates)}
+		}
+
+		sections = append(sections, section)
+	}
+
+	lvl[c.tagName("Sections")] = nbt.NewListTag(nbt.TAG_Compound, sections)
+}
+
+// readSectionBiomes reads the biomes compound of a 1.18+ section (a palette of biome names and packed indices)
+// into the biome cells starting at off.
+func (c *Chunk) readSectionBiomes(biomes nbt.TagCompound, off int) error {
+	paletteList, err := biomes.GetList("palette")
+	if err != nil {
+		return fmt.Errorf("Could not read sections -> biomes -> palette tag: %s", err)
+	}
+	if paletteList.Type != nbt.TAG_String || len(paletteList.Elems) == 0 {
+		return nil
+	}
+	palette := make([]Biome, len(paletteList.Elems))
+	for i, name := range paletteList.Elems {
+		palette[i] = BiomeByName(name.(string))
+	}
+
+	var indices []int
+	packed, err := getLongArray(biomes, "data")
+	switch {
+	case err == nil:
+		indices, err = unpackIndices(packed, bitsForPalette(len(palette), 1), biomeCellsInSection, false)
+		if err != nil {
+			return fmt.Errorf("Could not read sections -> biomes -> data tag: %s", err)
+		}
+	case err == nbt.NotFound && len(palette) == 1:
+		indices = make([]int, biomeCellsInSection)
+	default:
+		return fmt.Errorf("Could not read sections -> biomes -> data tag: %s", err)
+	}
+
+	for i, idx := range indices {
+		if idx >= len(palette) {
+			return fmt.Errorf("Invalid palette index %d in sections -> biomes", idx)
+		}
+		c.biomes[off+i] = palette[idx]
+	}
+	return nil
+}
+
+// sectionBiomesTag creates the biomes compound of a 1.18+ section from the biome cells starting at off.
+// Uncalculated biomes are stored as plains.
+func (c *Chunk) sectionBiomesTag(off int) nbt.TagCompound {
+	paletteIndices := make(map[Biome]int)
+	palette := make([]string, 0)
+	indices := make([]int, biomeCellsInSection)
+
+	for i := range indices {
+		bio := c.biomes[off+i]
+		name := bio.name118()
+		if name == "" {
+			bio = BioPlains
+			name = bio.name118()
+		}
+
+		idx, ok := paletteIndices[bio]
+		if !ok {
+			idx = len(palette)
+			paletteIndices[bio] = idx
+			palette = append(palette, name)
+		}
+		indices[i] = idx
+	}
+
+	tc := nbt.TagCompound{"palette": nbt.NewListTag(nbt.TAG_String, palette)}
+	if len(palette) > 1 {
+		tc["data"] = nbt.Tag{nbt.TAG_Long_Array, packIndices(indices, bitsForPalette(len(palette), 1), false)}
+	}
+	return tc
+}
@@ -0,0 +1,101 @@
+package mcmap
+
+import (
+	"github.com/silvasur/gonbt/nbt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// Blocks whose ID was changed after SetState must not be saved with their old state.
+func TestFlattenedStaleState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gomcmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	reg, err := OpenRegion(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg.SetChunkFormat(2586, 0, 256)
+
+	c, err := reg.NewChunk(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stone := NewBlockState("stone", nil)
+	for x := 0; x < 16; x++ {
+		c.Block(x, 0, 0).SetState(stone)
+	}
+	c.Block(5, 0, 0).ID = BlkDirt
+	c.MarkModified()
+	if err := c.MarkUnused(); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err = OpenRegion(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = reg.Chunk(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := c.Block(5, 0, 0).BlockState().Name; name != "minecraft:dirt" {
+		t.Errorf("Block (5, 0, 0) is %s, expected minecraft:dirt", name)
+	}
+	if name := c.Block(4, 0, 0).BlockState().Name; name != "minecraft:stone" {
+		t.Errorf("Block (4, 0, 0) is %s, expected minecraft:stone", name)
+	}
+}
+
+// Sections without light data must be read with full sky light and written without light arrays.
+func TestFlattenedMissingLight(t *testing.T) {
+	c := newChunk(nil, 0, 0)
+	c.dataVersion = 2586
+	c.status = "full"
+	c.Block(0, 0, 0).SetState(NewBlockState("stone", nil))
+	c.Block(0, 0, 0).SkyLight = 0
+
+	root, err := FlattenedChunkCodec.Encode(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections := root["Level"].Payload.(nbt.TagCompound)["Sections"].Payload.(nbt.TagList)
+	if len(sections.Elems) != 1 {
+		t.Fatalf("Got %d sections, expected 1", len(sections.Elems))
+	}
+	delete(sections.Elems[0].(nbt.TagCompound), "BlockLight")
+
+	c = &Chunk{dataVersion: 2586}
+	if err := FlattenedChunkCodec.Decode(root, c); err != nil {
+		t.Fatal(err)
+	}
+	if sky := c.Block(0, 100, 0).SkyLight; sky != maxLight {
+		t.Errorf("Sky light of a missing section is %d, expected %d", sky, maxLight)
+	}
+	if sky := c.Block(0, 0, 0).SkyLight; sky != 0 {
+		t.Errorf("Sky light of (0, 0, 0) is %d, expected 0", sky)
+	}
+
+	root, err = FlattenedChunkCodec.Encode(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections = root["Level"].Payload.(nbt.TagCompound)["Sections"].Payload.(nbt.TagList)
+	if len(sections.Elems) != 1 {
+		t.Fatalf("Got %d sections after the round trip, expected 1", len(sections.Elems))
+	}
+	section := sections.Elems[0].(nbt.TagCompound)
+	if _, ok := section["BlockLight"]; ok {
+		t.Errorf("Missing BlockLight array was written")
+	}
+	if _, ok := section["SkyLight"]; !ok {
+		t.Errorf("SkyLight array was not written")
+	}
+}
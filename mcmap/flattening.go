@@ -0,0 +1,630 @@
+package mcmap
+
+import (
+	"strconv"
+)
+
+// This file contains the mapping of numeric block IDs and data values (used until Minecraft 1.12)
+// to the block states used since Minecraft 1.13 ("The Flattening").
+//
+// Names and values from: http://minecraft.gamepedia.com/1.13/Flattening
+
+func st(name string, props ...string) *BlockState {
+	bs := &BlockState{Name: "minecraft:" + name}
+	if len(props) > 0 {
+		bs.Properties = make(map[string]string, len(props)/2)
+		for i := 0; i+1 < len(props); i += 2 {
+			bs.Properties[props[i]] = props[i+1]
+		}
+	}
+	return bs
+}
+
+func boolProp(b bool) string { return strconv.FormatBool(b) }
+func intProp(i int) string   { return strconv.Itoa(i) }
+
+func pick(names []string, i byte) string {
+	if int(i) < len(names) {
+		return names[i]
+	}
+	return ""
+}
+
+var (
+	railShapes       = []string{"north_south", "east_west", "ascending_east", "ascending_west", "ascending_north", "ascending_south", "south_east", "south_west", "north_west", "north_east"}
+	stoneSlabNames   = []string{"stone", "sandstone", "petrified_oak", "cobblestone", "brick", "stone_brick", "nether_brick", "quartz"}
+	stoneNames       = []string{"stone", "granite", "polished_granite", "diorite", "polished_diorite", "andesite", "polished_andesite"}
+	flowerNames      = []string{"poppy", "blue_orchid", "allium", "azure_bluet", "red_tulip", "orange_tulip", "white_tulip", "pink_tulip", "oxeye_daisy"}
+	doublePlantNames = []string{"sunflower", "lilac", "tall_grass", "large_fern", "rose_bush", "peony"}
+	monsterEggNames  = []string{"infested_stone", "infested_cobblestone", "infested_stone_bricks", "infested_mossy_stone_bricks", "infested_cracked_stone_bricks", "infested_chiseled_stone_bricks"}
+	stoneBrickNames  = []string{"stone_bricks", "mossy_stone_bricks", "cracked_stone_bricks", "chiseled_stone_bricks"}
+	prismarineNames  = []string{"prismarine", "prismarine_bricks", "dark_prismarine"}
+	anvilNames       = []string{"anvil", "chipped_anvil", "damaged_anvil"}
+	structureModes   = []string{"save", "load", "corner", "data"}
+	leverFaces       = []string{"ceiling", "wall", "wall", "wall", "wall", "floor", "floor", "ceiling"}
+	leverFacings     = []Facing{FacingWest, FacingEast, FacingWest, FacingSouth, FacingNorth, FacingNorth, FacingWest, FacingNorth}
+	skullNames       = []string{"skeleton_skull", "wither_skeleton_skull", "zombie_head", "player_head", "creeper_head", "dragon_head"}
+	wallSkullNames   = []string{"skeleton_wall_skull", "wither_skeleton_wall_skull", "zombie_wall_head", "player_wall_head", "creeper_wall_head", "dragon_wall_head"}
+	buttonFaces      = []string{"ceiling", "wall", "wall", "wall", "wall", "floor"}
+
+	// Sides of mushroom blocks with the outside texture (up, down, north, east, south, west), indexed by data value.
+	mushroomSides = [][6]bool{
+		{false, false, false, false, false, false},
+		{true, false, true, false, false, true},
+		{true, false, true, false, false, false},
+		{true, false, true, true, false, false},
+		{true, false, false, false, false, true},
+		{true, false, false, false, false, false},
+		{true, false, false, true, false, false},
+		{true, false, false, false, true, true},
+		{true, false, false, false, true, false},
+		{true, false, false, true, true, false},
+		{false, false, true, true, true, true},
+		{}, {}, {},
+		{true, true, true, true, true, true},
+		{true, true, true, true, true, true},
+	}
+
+	stairNames = map[BlockID]string{
+		BlkOakWoodStairs:      "oak_stairs",
+		BlkCobblestoneStairs:  "cobblestone_stairs",
+		BlkBrickStairs:        "brick_stairs",
+		BlkStoneBrickStairs:   "stone_brick_stairs",
+		BlkNetherBrickStairs:  "nether_brick_stairs",
+		BlkSandstoneStairs:    "sandstone_stairs",
+		BlkSpruceWoodStairs:   "spruce_stairs",
+		BlkBirchWoodStairs:    "birch_stairs",
+		BlkJungleWoodStairs:   "jungle_stairs",
+		BlkQuartzStairs:       "quartz_stairs",
+		BlkAcaciaWoodStairs:   "acacia_stairs",
+		BlkDarkOakWoodStairs:  "dark_oak_stairs",
+		BlkRedSandstoneStairs: "red_sandstone_stairs",
+		BlkPurpurStairs:       "purpur_stairs",
+	}
+
+	// Blocks that were simply renamed and have no properties.
+	simpleBlockNames = map[BlockID]string{
+		BlkAir:                "air",
+		BlkCobblestone:        "cobblestone",
+		BlkBedrock:            "bedrock",
+		BlkGravel:             "gravel",
+		BlkGoldOre:            "gold_ore",
+		BlkIronOre:            "iron_ore",
+		BlkCoalOre:            "coal_ore",
+		BlkGlass:              "glass",
+		BlkLapisLazuliOre:     "lapis_ore",
+		BlkLapisLazuliBlock:   "lapis_block",
+		BlkNoteBlock:          "note_block",
+		BlkCobweb:             "cobweb",
+		BlkDeadBush:           "dead_bush",
+		BlkDandelion:          "dandelion",
+		BlkBrownMushroom:      "brown_mushroom",
+		BlkRedMushroom:        "red_mushroom",
+		BlkBlockOfGold:        "gold_block",
+		BlkBlockOfIron:        "iron_block",
+		BlkBricks:             "bricks",
+		BlkBookshelf:          "bookshelf",
+		BlkMossStone:          "mossy_cobblestone",
+		BlkObsidian:           "obsidian",
+		BlkMonsterSpawner:     "spawner",
+		BlkDiamondOre:         "diamond_ore",
+		BlkBlockOfDiamond:     "diamond_block",
+		BlkCraftingTable:      "crafting_table",
+		BlkIce:                "ice",
+		BlkSnowBlock:          "snow_block",
+		BlkClay:               "clay",
+		BlkNetherrack:         "netherrack",
+		BlkSoulSand:           "soul_sand",
+		BlkGlowstone:          "glowstone",
+		BlkIronBars:           "iron_bars",
+		BlkGlassPane:          "glass_pane",
+		BlkMelon:              "melon",
+		BlkLilyPad:            "lily_pad",
+		BlkNetherBrick:        "nether_bricks",
+		BlkNetherBrickFence:   "nether_brick_fence",
+		BlkEnchantmentTable:   "enchanting_table",
+		BlkEndPortal:          "end_portal",
+		BlkEndStone:           "end_stone",
+		BlkDragonEgg:          "dragon_egg",
+		BlkEmeraldOre:         "emerald_ore",
+		BlkBlockOfEmerald:     "emerald_block",
+		BlkBeacon:             "beacon",
+		BlkFlowerPot:          "flower_pot",
+		BlkBlockOfRedstone:    "redstone_block",
+		BlkNetherQuartzOre:    "nether_quartz_ore",
+		BlkSlimeBlock:         "slime_block",
+		BlkBarrier:            "barrier",
+		BlkSeaLantern:         "sea_lantern",
+		BlkHardenedClay:       "terracotta",
+		BlkBlockOfCoal:        "coal_block",
+		BlkPackedIce:          "packed_ice",
+		BlkChorusPlant:        "chorus_plant",
+		BlkPurpurBlock:        "purpur_block",
+		BlkEndStoneBricks:     "end_stone_bricks",
+		BlkGrassPath:          "grass_path",
+		BlkEndGateway:         "end_gateway",
+		BlkMagmaBlock:         "magma_block",
+		BlkNetherWartBlock:    "nether_wart_block",
+		BlkRedNetherBrick:     "red_nether_bricks",
+		BlkStructureVoid:      "structure_void",
+		BlkSpruceFence:        "spruce_fence",
+		BlkBirchFence:         "birch_fence",
+		BlkJungleFence:        "jungle_fence",
+		BlkDarkOakFence:       "dark_oak_fence",
+		BlkAcaciaFence:        "acacia_fence",
+		BlkFence:              "oak_fence",
+		BlkBlockMovedByPiston: "moving_piston",
+	}
+
+	// Blocks with a color in the data value. The name is prefixed with the color.
+	coloredBlockNames = map[BlockID]string{
+		BlkWool:             "wool",
+		BlkStainedGlass:     "stained_glass",
+		BlkStainedClay:      "terracotta",
+		BlkStainedGlassPane: "stained_glass_pane",
+		BlkCarpet:           "carpet",
+		BlkConcrete:         "concrete",
+		BlkConcretePowder:   "concrete_powder",
+	}
+)
+
+// facingOf returns the facing of a legacy block as a property value.
+func facingOf(id BlockID, data byte) string {
+	if f, ok := (Block{ID: id, Data: data}).Facing(); ok {
+		return f.String()
+	}
+	return FacingNorth.String()
+}
+
+// horizontalFacingOf is facingOf for blocks that can not face down or up (chests, furnaces, ...). These facings become north.
+func horizontalFacingOf(id BlockID, data byte) string {
+	if f, ok := (Block{ID: id, Data: data}).Facing(); ok && f != FacingDown && f != FacingUp {
+		return f.String()
+	}
+	return FacingNorth.String()
+}
+
+func halfOf(id BlockID, data byte) Half {
+	h, _ := (Block{ID: id, Data: data}).Half()
+	return h
+}
+
+func woodOf(id BlockID, data byte) string {
+	w, _ := (Block{ID: id, Data: data}).WoodType()
+	return w.String()
+}
+
+func axisOf(id BlockID, data byte) string {
+	a, _ := (Block{ID: id, Data: data}).Axis()
+	return a.String()
+}
+
+func slabType(data byte) string {
+	if data&8 != 0 {
+		return "top"
+	}
+	return "bottom"
+}
+
+func doorState(name string, id BlockID, data byte) *BlockState {
+	if data&8 != 0 {
+		hinge := HingeLeft
+		if data&1 != 0 {
+			hinge = HingeRight
+		}
+		return st(name, "half", "upper", "hinge", hinge.String(), "powered", boolProp(data&2 != 0))
+	}
+	return st(name, "half", "lower", "facing", facingOf(id, data), "open", boolProp(data&4 != 0))
+}
+
+func railState(name string, data byte, powerable bool) *BlockState {
+	if !powerable {
+		return st(name, "shape", pick(railShapes, data))
+	}
+	return st(name, "shape", pick(railShapes, data&7), "powered", boolProp(data&8 != 0))
+}
+
+func torchState(name string, id BlockID, data byte, lit ...string) *BlockState {
+	if data == 5 || data == 0 {
+		return st(name, lit...)
+	}
+	wallName := name[:len(name)-len("torch")] + "wall_torch"
+	return st(wallName, append([]string{"facing", facingOf(id, data)}, lit...)...)
+}
+
+func buttonState(name string, data byte) *BlockState {
+	face := pick(buttonFaces, data&7)
+	if face == "" {
+		return nil
+	}
+	facing := FacingNorth.String()
+	if face == "wall" {
+		facing = facingOf(BlkStoneButton, data)
+	}
+	return st(name, "face", face, "facing", facing, "powered", boolProp(data&8 != 0))
+}
+
+func mushroomState(name string, data byte) *BlockState {
+	sides := mushroomSides[data]
+	if data >= 11 && data <= 13 {
+		// Unused data values, treat them as "all sides"
+		sides = mushroomSides[14]
+	}
+	if data == 10 || data == 15 {
+		name = "mushroom_stem"
+	}
+	return st(name,
+		"up", boolProp(sides[0]), "down", boolProp(sides[1]),
+		"north", boolProp(sides[2]), "east", boolProp(sides[3]),
+		"south", boolProp(sides[4]), "west", boolProp(sides[5]))
+}
+
+// legacyBlockState returns the block state of a numeric ID and data value. Returns nil, if there is no equivalent.
+//
+// Some blocks store additional information in their tile entity (beds, banners, skulls, flower pots).
+// For these, a default state is returned.
+func legacyBlockState(id BlockID, data byte) *BlockState {
+	bs := flattenBlock(id, data&0xf)
+	if bs == nil || bs.Name == "minecraft:" {
+		// Invalid data value
+		return nil
+	}
+	return bs
+}
+
+// tileEntityStateAliases returns the block states, whose numeric ID and data value are the same as the ones of
+// another state, because the difference is stored in the tile entity (see UpgradeChunk).
+// The keys are the states returned by legacyBlockState.
+func tileEntityStateAliases() map[string][]string {
+	aliases := map[string][]string{
+		"skeleton_skull":      skullNames[1:],
+		"skeleton_wall_skull": wallSkullNames[1:],
+		"sunflower":           doublePlantNames[1:], // Upper halves
+		"flower_pot":          pottedPlantNames(),
+	}
+	for col := ColorWhite; col <= ColorBlack; col++ {
+		if col != ColorRed {
+			aliases["red_bed"] = append(aliases["red_bed"], col.String()+"_bed")
+		}
+		if col != ColorWhite {
+			aliases["white_banner"] = append(aliases["white_banner"], col.String()+"_banner")
+			aliases["white_wall_banner"] = append(aliases["white_wall_banner"], col.String()+"_wall_banner")
+		}
+	}
+	return aliases
+}
+
+func pottedPlantNames() []string {
+	names := []string{"potted_dandelion", "potted_red_mushroom", "potted_brown_mushroom", "potted_dead_bush", "potted_cactus", "potted_fern"}
+	for wood := WoodOak; wood <= WoodDarkOak; wood++ {
+		names = append(names, "potted_"+wood.String()+"_sapling")
+	}
+	for _, flower := range flowerNames {
+		names = append(names, "potted_"+flower)
+	}
+	return names
+}
+
+func flattenBlock(id BlockID, data byte) *BlockState {
+	if name, ok := simpleBlockNames[id]; ok {
+		return st(name)
+	}
+	if name, ok := coloredBlockNames[id]; ok {
+		return st(Color(data).String() + "_" + name)
+	}
+	if name, ok := stairNames[id]; ok {
+		return st(name, "facing", facingOf(id, data), "half", halfOf(id, data).String())
+	}
+
+	switch id {
+	case BlkStone:
+		return st(pick(stoneNames, data))
+	case BlkGrassBlock:
+		return st("grass_block", "snowy", "false")
+	case BlkDirt:
+		switch data {
+		case 1:
+			return st("coarse_dirt")
+		case 2:
+			return st("podzol", "snowy", "false")
+		}
+		return st("dirt")
+	case BlkWoodPlanks:
+		return st(woodOf(id, data) + "_planks")
+	case BlkSaplings:
+		return st(woodOf(id, data)+"_sapling", "stage", intProp(int(data>>3)))
+	case BlkWater, BlkStationaryWater:
+		return st("water", "level", intProp(int(data)))
+	case BlkLava, BlkStationaryLava:
+		return st("lava", "level", intProp(int(data)))
+	case BlkSand:
+		if data == 1 {
+			return st("red_sand")
+		}
+		return st("sand")
+	case BlkWood, BlkWood2:
+		if a, _ := (Block{ID: id, Data: data}).Axis(); a == AxisNone {
+			return st(woodOf(id, data)+"_wood", "axis", "y")
+		}
+		return st(woodOf(id, data)+"_log", "axis", axisOf(id, data))
+	case BlkLeaves, BlkLeaves2:
+		return st(woodOf(id, data)+"_leaves", "persistent", boolProp(data&4 != 0))
+	case BlkSponge:
+		if data == 1 {
+			return st("wet_sponge")
+		}
+		return st("sponge")
+	case BlkDispenser, BlkDropper:
+		name := "dispenser"
+		if id == BlkDropper {
+			name = "dropper"
+		}
+		return st(name, "facing", facingOf(id, data), "triggered", boolProp(data&8 != 0))
+	case BlkSandstone, BlkRedSandstone:
+		prefix := ""
+		if id == BlkRedSandstone {
+			prefix = "red_"
+		}
+		switch data {
+		case 1:
+			return st("chiseled_" + prefix + "sandstone")
+		case 2:
+			return st("cut_" + prefix + "sandstone")
+		}
+		return st(prefix + "sandstone")
+	case BlkBed:
+		part := "foot"
+		if data&8 != 0 {
+			part = "head"
+		}
+		return st("red_bed", "facing", facingOf(id, data), "part", part, "occupied", boolProp(data&4 != 0))
+	case BlkPoweredRail:
+		return railState("powered_rail", data, true)
+	case BlkDetectorRail:
+		return railState("detector_rail", data, true)
+	case BlkActivatorRail:
+		return railState("activator_rail", data, true)
+	case BlkRail:
+		return railState("rail", data, false)
+	case BlkPiston, BlkStickyPiston:
+		name := "piston"
+		if id == BlkStickyPiston {
+			name = "sticky_piston"
+		}
+		return st(name, "facing", facingOf(id, data), "extended", boolProp(data&8 != 0))
+	case BlkPistonExtension:
+		typ := "normal"
+		if data&8 != 0 {
+			typ = "sticky"
+		}
+		return st("piston_head", "facing", facingOf(id, data), "type", typ)
+	case BlkGrass:
+		switch data {
+		case 0:
+			return st("dead_bush")
+		case 2:
+			return st("fern")
+		}
+		return st("grass")
+	case BlkFlower:
+		return st(pick(flowerNames, data))
+	case BlkDoubleSlabs:
+		switch data {
+		case 8:
+			return st("smooth_stone")
+		case 9:
+			return st("smooth_sandstone")
+		case 15:
+			return st("smooth_quartz")
+		}
+		return st(pick(stoneSlabNames, data&7)+"_slab", "type", "double")
+	case BlkSlabs:
+		return st(pick(stoneSlabNames, data&7)+"_slab", "type", slabType(data))
+	case BlkDoubleRedSandstoneSlab:
+		if data == 8 {
+			return st("smooth_red_sandstone")
+		}
+		return st("red_sandstone_slab", "type", "double")
+	case BlkRedSandstoneSlab:
+		return st("red_sandstone_slab", "type", slabType(data))
+	case BlkWoodenDoubleSlab:
+		return st(woodOf(id, data)+"_slab", "type", "double")
+	case BlkWoodenSlab:
+		return st(woodOf(id, data)+"_slab", "type", slabType(data))
+	case BlkPurpurDoubleSlab:
+		return st("purpur_slab", "type", "double")
+	case BlkPurpurSlab:
+		return st("purpur_slab", "type", slabType(data))
+	case BlkTNT:
+		return st("tnt", "unstable", boolProp(data&1 != 0))
+	case BlkTorch:
+		return torchState("torch", id, data)
+	case BlkRedstoneTorchInactive:
+		return torchState("redstone_torch", id, data, "lit", "false")
+	case BlkRedstoneTorchActive:
+		return torchState("redstone_torch", id, data, "lit", "true")
+	case BlkFire:
+		return st("fire", "age", intProp(int(data)))
+	case BlkChest, BlkTrappedChest:
+		name := "chest"
+		if id == BlkTrappedChest {
+			name = "trapped_chest"
+		}
+		return st(name, "facing", horizontalFacingOf(id, data), "type", "single")
+	case BlkEnderChest:
+		return st("ender_chest", "facing", horizontalFacingOf(id, data))
+	case BlkRedstoneWire:
+		return st("redstone_wire", "power", intProp(int(data)))
+	case BlkWheat, BlkCarrots, BlkPotatoes:
+		name := map[BlockID]string{BlkWheat: "wheat", BlkCarrots: "carrots", BlkPotatoes: "potatoes"}[id]
+		return st(name, "age", intProp(int(data&7)))
+	case BlkBeetroots:
+		return st("beetroots", "age", intProp(int(data&3)))
+	case BlkFarmland:
+		return st("farmland", "moisture", intProp(int(data&7)))
+	case BlkFurnace, BlkBurningFurnace:
+		return st("furnace", "facing", horizontalFacingOf(id, data), "lit", boolProp(id == BlkBurningFurnace))
+	case BlkSignPost:
+		return st("sign", "rotation", intProp(int(data)))
+	case BlkWallSign:
+		return st("wall_sign", "facing", horizontalFacingOf(id, data))
+	case BlkWoodenDoor:
+		return doorState("oak_door", id, data)
+	case BlkIronDoor:
+		return doorState("iron_door", id, data)
+	case BlkSpruceDoor, BlkBirchDoor, BlkJungleDoor, BlkAcaciaDoor, BlkDarkOakDoor:
+		return doorState(woodOf(id, data)+"_door", id, data)
+	case BlkLadders:
+		return st("ladder", "facing", horizontalFacingOf(id, data))
+	case BlkLever:
+		return st("lever", "face", leverFaces[data&7], "facing", leverFacings[data&7].String(), "powered", boolProp(data&8 != 0))
+	case BlkStonePressurePlate:
+		return st("stone_pressure_plate", "powered", boolProp(data&1 != 0))
+	case BlkWoodenPressurePlate:
+		return st("oak_pressure_plate", "powered", boolProp(data&1 != 0))
+	case BlkWeightedPressurePlateLight:
+		return st("light_weighted_pressure_plate", "power", intProp(int(data)))
+	case BlkWeightedPressurePlateHeavy:
+		return st("heavy_weighted_pressure_plate", "power", intProp(int(data)))
+	case BlkRedstoneOre, BlkGlowingRedstoneOre:
+		return st("redstone_ore", "lit", boolProp(id == BlkGlowingRedstoneOre))
+	case BlkStoneButton:
+		return buttonState("stone_button", data)
+	case BlkWoodenButton:
+		return buttonState("oak_button", data)
+	case BlkSnow:
+		return st("snow", "layers", intProp(int(data&7)+1))
+	case BlkCactus:
+		return st("cactus", "age", intProp(int(data)))
+	case BlkSugarCane:
+		return st("sugar_cane", "age", intProp(int(data)))
+	case BlkJukebox:
+		return st("jukebox", "has_record", boolProp(data&1 != 0))
+	case BlkPumpkin:
+		return st("carved_pumpkin", "facing", facingOf(id, data))
+	case BlkJackOLantern:
+		return st("jack_o_lantern", "facing", facingOf(id, data))
+	case BlkNetherPortal:
+		if data == 2 {
+			return st("nether_portal", "axis", "z")
+		}
+		return st("nether_portal", "axis", "x")
+	case BlkCakeBlock:
+		return st("cake", "bites", intProp(int(data&7)))
+	case BlkRedstoneRepeaterInactive, BlkRedstoneRepeaterActive:
+		return st("repeater", "facing", facingOf(id, data), "delay", intProp(int(data>>2)+1), "powered", boolProp(id == BlkRedstoneRepeaterActive))
+	case BlkRedstoneComparatorInactive, BlkRedstoneComparatorActive:
+		mode := "compare"
+		if data&4 != 0 {
+			mode = "subtract"
+		}
+		return st("comparator", "facing", facingOf(id, data), "mode", mode, "powered", boolProp(data&8 != 0))
+	case BlkTrapdoor, BlkIronTrapdoor:
+		name := "oak_trapdoor"
+		if id == BlkIronTrapdoor {
+			name = "iron_trapdoor"
+		}
+		return st(name, "facing", facingOf(id, data), "half", halfOf(id, data).String(), "open", boolProp(data&4 != 0))
+	case BlkMonsterEgg:
+		return st(pick(monsterEggNames, data))
+	case BlkStoneBricks:
+		return st(pick(stoneBrickNames, data))
+	case BlkHugeBrownMushroom:
+		return mushroomState("brown_mushroom_block", data)
+	case BlkHugeRedMushroom:
+		return mushroomState("red_mushroom_block", data)
+	case BlkPumpkinStem:
+		return st("pumpkin_stem", "age", intProp(int(data&7)))
+	case BlkMelonStem:
+		return st("melon_stem", "age", intProp(int(data&7)))
+	case BlkVines:
+		return st("vine", "south", boolProp(data&1 != 0), "west", boolProp(data&2 != 0), "north", boolProp(data&4 != 0), "east", boolProp(data&8 != 0))
+	case BlkFenceGate, BlkSpruceFenceGate, BlkBirchFenceGate, BlkJungleFenceGate, BlkDarkOakFenceGate, BlkAcaciaFenceGate:
+		return st(woodOf(id, data)+"_fence_gate", "facing", facingOf(id, data), "open", boolProp(data&4 != 0), "powered", boolProp(data&8 != 0))
+	case BlkMycelium:
+		return st("mycelium", "snowy", "false")
+	case BlkNetherWart:
+		return st("nether_wart", "age", intProp(int(data&3)))
+	case BlkBrewingStand:
+		return st("brewing_stand", "has_bottle_0", boolProp(data&1 != 0), "has_bottle_1", boolProp(data&2 != 0), "has_bottle_2", boolProp(data&4 != 0))
+	case BlkCauldron:
+		return st("cauldron", "level", intProp(int(data&3)))
+	case BlkEndPortalBlock:
+		return st("end_portal_frame", "facing", facingOf(id, data), "eye", boolProp(data&4 != 0))
+	case BlkRedstoneLampInactive, BlkRedstoneLampActive:
+		return st("redstone_lamp", "lit", boolProp(id == BlkRedstoneLampActive))
+	case BlkCocoa:
+		return st("cocoa", "facing", facingOf(id, data), "age", intProp(int(data>>2)))
+	case BlkTripwireHook:
+		return st("tripwire_hook", "facing", facingOf(id, data), "attached", boolProp(data&4 != 0), "powered", boolProp(data&8 != 0))
+	case BlkTripwire:
+		return st("tripwire", "powered", boolProp(data&1 != 0), "attached", boolProp(data&4 != 0), "disarmed", boolProp(data&8 != 0))
+	case BlkCommandBlock:
+		return st("command_block", "facing", facingOf(id, data), "conditional", boolProp(data&8 != 0))
+	case BlkRepeatingCommandBlock:
+		return st("repeating_command_block", "facing", facingOf(id, data), "conditional", boolProp(data&8 != 0))
+	case BlkChainCommandBlock:
+		return st("chain_command_block", "facing", facingOf(id, data), "conditional", boolProp(data&8 != 0))
+	case BlkCobblestoneWall:
+		if data == 1 {
+			return st("mossy_cobblestone_wall")
+		}
+		return st("cobblestone_wall")
+	case BlkMobHead:
+		if data&7 == 1 {
+			return st("skeleton_skull", "rotation", "0")
+		}
+		return st("skeleton_wall_skull", "facing", facingOf(id, data))
+	case BlkAnvil:
+		return st(pick(anvilNames, data>>2), "facing", facingOf(id, data))
+	case BlkDaylightSensor, BlkInvertedDaylightSensor:
+		return st("daylight_detector", "power", intProp(int(data)), "inverted", boolProp(id == BlkInvertedDaylightSensor))
+	case BlkHopper:
+		return st("hopper", "facing", facingOf(id, data), "enabled", boolProp(data&8 == 0))
+	case BlkBlockOfQuartz:
+		switch data {
+		case 1:
+			return st("chiseled_quartz_block")
+		case 2, 3, 4:
+			return st("quartz_pillar", "axis", axisOf(id, data))
+		}
+		return st("quartz_block")
+	case BlkPrismarine:
+		return st(pick(prismarineNames, data))
+	case BlkHayBlock:
+		return st("hay_block", "axis", axisOf(id, data))
+	case BlkBoneBlock:
+		return st("bone_block", "axis", axisOf(id, data))
+	case BlkPurpurPillar:
+		return st("purpur_pillar", "axis", axisOf(id, data))
+	case BlkLargeFlower:
+		if data&8 != 0 {
+			// The type of the upper half is determined by the lower half.
+			return st("sunflower", "half", "upper")
+		}
+		return st(pick(doublePlantNames, data&7), "half", "lower")
+	case BlkStandingBanner:
+		return st("white_banner", "rotation", intProp(int(data)))
+	case BlkWallBanner:
+		return st("white_wall_banner", "facing", horizontalFacingOf(id, data))
+	case BlkEndRod:
+		return st("end_rod", "facing", facingOf(id, data))
+	case BlkChorusFlower:
+		return st("chorus_flower", "age", intProp(int(data&7)))
+	case BlkFrostedIce:
+		return st("frosted_ice", "age", intProp(int(data&3)))
+	case BlkObserver:
+		return st("observer", "facing", facingOf(id, data), "powered", boolProp(data&8 != 0))
+	case BlkStructureBlock:
+		return st("structure_block", "mode", pick(structureModes, data&3))
+	}
+
+	if id >= BlkWhiteShulkerBox && id <= BlkBlackShulkerBox {
+		return st(Color(id-BlkWhiteShulkerBox).String()+"_shulker_box", "facing", facingOf(id, data))
+	}
+	if id >= BlkWhiteGlazedTerracotta && id <= BlkBlackGlazedTerracotta {
+		return st(Color(id-BlkWhiteGlazedTerracotta).String()+"_glazed_terracotta", "facing", facingOf(id, data))
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package mcmap
+
+import (
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"strings"
+)
+
+// Prefixes of the keys in the FML.ItemData list (Forge for Minecraft 1.7).
+const (
+	fmlBlockPrefix = "\x01"
+	fmlItemPrefix  = "\x02"
+)
+
+func readFMLIDList(list nbt.TagList, prefix string) (map[string]BlockID, error) {
+	ids := make(map[string]BlockID)
+	if list.Type != nbt.TAG_Compound {
+		return ids, nil
+	}
+
+	for _, _entry := range list.Elems {
+		entry := _entry.(nbt.TagCompound)
+
+		k, err := entry.GetString("K")
+		if err != nil {
+			return nil, fmt.Errorf("Could not read K tag of a Forge ID mapping: %s", err)
+		}
+		v, err := entry.GetInt("V")
+		if err != nil {
+			return nil, fmt.Errorf("Could not read V tag of a Forge ID mapping: %s", err)
+		}
+
+		if prefix != "" {
+			if !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			k = k[len(prefix):]
+		}
+
+		ids[NormalizeBlockName(k)] = BlockID(v)
+	}
+
+	return ids, nil
+}
+
+// ReadForgeBlockIDs reads the block ID mapping Forge stores in level.dat. level is the root compound of level.dat.
+//
+// Both the FML.ItemData list (Minecraft 1.7) and the FML.Registries compound (Minecraft 1.8 - 1.12) are supported.
+// If level.dat contains no mapping, NotAvailable is returned.
+func ReadForgeBlockIDs(level nbt.TagCompound) (map[string]BlockID, error) {
+	fml, err := level.GetCompound("FML")
+	switch err {
+	case nil:
+	case nbt.NotFound:
+		return nil, NotAvailable
+	default:
+		return nil, fmt.Errorf("Could not read FML tag: %s", err)
+	}
+
+	registries, err := fml.GetCompound("Registries")
+	switch err {
+	case nil:
+		blocks, err := registries.GetCompound("minecraft:blocks")
+		if err != nil {
+			return nil, fmt.Errorf("Could not read FML -> Registries -> minecraft:blocks tag: %s", err)
+		}
+		list, err := blocks.GetList("ids")
+		if err != nil {
+			return nil, fmt.Errorf("Could not read FML -> Registries -> minecraft:blocks -> ids tag: %s", err)
+		}
+		return readFMLIDList(list, "")
+	case nbt.NotFound:
+	default:
+		return nil, fmt.Errorf("Could not read FML -> Registries tag: %s", err)
+	}
+
+	list, err := fml.GetList("ItemData")
+	switch err {
+	case nil:
+		return readFMLIDList(list, fmlBlockPrefix)
+	case nbt.NotFound:
+		return nil, NotAvailable
+	default:
+		return nil, fmt.Errorf("Could not read FML -> ItemData tag: %s", err)
+	}
+}
+
+// BlockRemap maps block IDs of one world to the IDs of the same blocks in another world.
+// IDs that are not in the map are left unchanged.
+type BlockRemap map[BlockID]BlockID
+
+// NewBlockRemap creates a BlockRemap by matching the names of the blocks in the two registries.
+// unmapped contains the names of the blocks in from, that are not available in to.
+func NewBlockRemap(from, to *BlockRegistry) (remap BlockRemap, unmapped []string) {
+	remap = make(BlockRemap)
+
+	for fromID, name := range from.IDs() {
+		toID, ok := to.ID(name)
+		if !ok {
+			unmapped = append(unmapped, name)
+			continue
+		}
+		if toID != fromID {
+			remap[fromID] = toID
+		}
+	}
+
+	return
+}
+
+// Block remaps the ID of a block. It returns true, if the ID was changed.
+func (remap BlockRemap) Block(blk *Block) bool {
+	id, ok := remap[blk.ID]
+	if !ok || id == blk.ID {
+		return false
+	}
+	blk.ID = id
+	return true
+}
+
+// Chunk remaps all blocks of a chunk. If a block was changed, the chunk will be marked as modified.
+func (remap BlockRemap) Chunk(c *Chunk) {
+	if len(remap) == 0 {
+		return
+	}
+
+	modified := false
+	c.Iter(func(x, y, z int, blk *Block) {
+		if remap.Block(blk) {
+			modified = true
+		}
+	})
+
+	if modified {
+		c.MarkModified()
+	}
+}
@@ -0,0 +1,153 @@
+package mcmap
+
+import (
+	"errors"
+	"github.com/silvasur/gonbt/nbt"
+	"strings"
+)
+
+// HeightMapType selects one of the height maps of a chunk. A height map contains the Y coordinate above the
+// highest block of a column matching some criteria (or MinY of the chunk, if there is no such block).
+type HeightMapType int
+
+const (
+	HeightMapLightBlocking          HeightMapType = iota // Highest block absorbing light. Used before Minecraft 1.13.
+	HeightMapWorldSurface                                // Highest non-air block.
+	HeightMapOceanFloor                                  // Highest solid block.
+	HeightMapMotionBlocking                              // Highest solid or liquid block.
+	HeightMapMotionBlockingNoLeaves                      // Like HeightMapMotionBlocking, but ignoring leaves.
+	numHeightMapTypes
+)
+
+var heightMapNames = [numHeightMapTypes]string{"LIGHT_BLOCKING", "WORLD_SURFACE", "OCEAN_FLOOR", "MOTION_BLOCKING", "MOTION_BLOCKING_NO_LEAVES"}
+
+// String returns the name of the height map, as used in the Heightmaps compound of chunks.
+func (t HeightMapType) String() string {
+	if t >= 0 && t < numHeightMapTypes {
+		return heightMapNames[t]
+	}
+	return "(unknown)"
+}
+
+// Height maps stored in the Heightmaps compound of 1.13+ chunks.
+var flattenedHeightMaps = []HeightMapType{HeightMapWorldSurface, HeightMapOceanFloor, HeightMapMotionBlocking, HeightMapMotionBlockingNoLeaves}
+
+// Names of the air blocks (air in caves and outside of the world were added in 1.13).
+var airBlockNames = map[string]bool{
+	"minecraft:air":      true,
+	"minecraft:cave_air": true,
+	"minecraft:void_air": true,
+}
+
+// The block states are the ones returned by Block.BlockState, so they match the ID and data value of the block.
+
+func isAirState(bs *BlockState) bool {
+	return airBlockNames[bs.Name]
+}
+
+func isLeavesState(bs *BlockState) bool {
+	return strings.HasSuffix(bs.Name, "_leaves")
+}
+
+func isWaterlogged(bs *BlockState) bool {
+	return bs.Property("waterlogged") == "true"
+}
+
+// matches checks, if a block with the state bs counts for the height map.
+func (t HeightMapType) matches(bs *BlockState, props BlockProperties) bool {
+	switch t {
+	case HeightMapLightBlocking:
+		return props.Opacity > 0
+	case HeightMapWorldSurface:
+		return !isAirState(bs)
+	case HeightMapOceanFloor:
+		return props.Solid
+	case HeightMapMotionBlocking:
+		return props.Solid || props.Liquid || isWaterlogged(bs)
+	case HeightMapMotionBlockingNoLeaves:
+		return (props.Solid || props.Liquid || isWaterlogged(bs)) && !isLeavesState(bs)
+	}
+	return false
+}
+
+// RecalcHeightMap recalculates all height maps.
+//
+// Usually you don't need to call this, the height maps get recalculated automatically after the chunk was marked as modified.
+func (c *Chunk) RecalcHeightMap() {
+	for t := range c.heightMaps {
+		if len(c.heightMaps[t]) != ChunkRectXZ {
+			c.heightMaps[t] = make([]int32, ChunkRectXZ)
+		}
+	}
+
+	// Caches the block states, calculating them for every block is expensive.
+	states := make(map[blockKey]*BlockState)
+
+	i := 0
+	for z := 0; z < ChunkSizeXZ; z++ {
+		for x := 0; x < ChunkSizeXZ; x++ {
+			var found [numHeightMapTypes]bool
+			todo := len(found)
+			for t := range c.heightMaps {
+				c.heightMaps[t][i] = int32(c.minY)
+			}
+
+			for y := c.MaxY(); y >= c.MinY() && todo > 0; y-- {
+				blk := &c.blocks[c.blockOffset(x, y, z)]
+				key := stateKey(*blk)
+				bs, ok := states[key]
+				if !ok {
+					bs = blk.BlockState()
+					states[key] = bs
+				}
+				props := blk.ID.Properties()
+				for t := range c.heightMaps {
+					if !found[t] && HeightMapType(t).matches(bs, props) {
+						found[t] = true
+						todo--
+						c.heightMaps[t][i] = int32(y + 1)
+					}
+				}
+			}
+
+			i++
+		}
+	}
+
+	c.heightMapsValid = true
+}
+
+func (c *Chunk) updateHeightMaps() {
+	if !c.heightMapsValid {
+		c.RecalcHeightMap()
+	}
+}
+
+// HeightOf returns the value of a height map at x, z.
+//
+// x and z must be in [0, 15]. HeightOf will panic, if this is violated!
+func (c *Chunk) HeightOf(t HeightMapType, x, z int) int {
+	if (x < 0) || (x >= ChunkSizeXZ) || (z < 0) || (z >= ChunkSizeXZ) {
+		panic(errors.New("x or z parameter was out of range"))
+	}
+
+	c.updateHeightMaps()
+	return int(c.heightMaps[t][z*ChunkSizeXZ+x])
+}
+
+// heightMapsTag creates the Heightmaps compound of 1.13+ chunks. The heights are relative to MinY
+// and packed like the block state indices.
+func (c *Chunk) heightMapsTag() nbt.TagCompound {
+	c.updateHeightMaps()
+
+	bits := bitsForPalette(c.sizeY+1, 1)
+	tc := make(nbt.TagCompound)
+	for _, t := range flattenedHeightMaps {
+		vals := make([]int, ChunkRectXZ)
+		for i, h := range c.heightMaps[t] {
+			vals[i] = int(h) - c.minY
+		}
+		tc[t.String()] = nbt.Tag{nbt.TAG_Long_Array, packIndices(vals, bits, c.dataVersion < dataVersionNonSpanningStates)}
+	}
+	return tc
+}
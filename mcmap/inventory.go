@@ -0,0 +1,158 @@
+package mcmap
+
+import (
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+)
+
+// Inventory gives access to the slots of a container, a mob's equipment, a player inventory, ...
+// Changes are written directly to the NBT data of the owner.
+type Inventory interface {
+	// Size returns the number of slots (0, if unknown).
+	Size() int
+
+	// Item returns the item in a slot (nil, if the slot is empty or the item is invalid, see Items).
+	// Changes to the item need to be stored with SetItem.
+	Item(slot int) *ItemStack
+
+	// SetItem puts an item into a slot, replacing the previous item. A nil item empties the slot.
+	SetItem(slot int, item *ItemStack)
+
+	// Items returns the items of all non-empty slots. If an item can not be read, the other items are returned
+	// together with the error.
+	Items() (map[int]*ItemStack, error)
+}
+
+// slotInventory is an inventory stored as a list of items with Slot tags (containers, player inventories, ...).
+type slotInventory struct {
+	tc   nbt.TagCompound
+	name string
+	size int
+}
+
+func (inv slotInventory) Size() int { return inv.size }
+
+func (inv slotInventory) list() []nbt.TagCompound {
+	return tileEntity(inv.tc).getCompoundList(inv.name)
+}
+
+func itemSlot(tc nbt.TagCompound) (int, bool) {
+	slot, err := tc.GetByte("Slot")
+	return int(int8(slot)), err == nil
+}
+
+func (inv slotInventory) Item(slot int) *ItemStack {
+	for _, tc := range inv.list() {
+		if s, ok := itemSlot(tc); ok && s == slot {
+			it, _ := ItemStackFromNBT(tc)
+			return it
+		}
+	}
+	return nil
+}
+
+func (inv slotInventory) SetItem(slot int, item *ItemStack) {
+	var items []nbt.TagCompound
+	for _, tc := range inv.list() {
+		if s, ok := itemSlot(tc); !ok || s != slot {
+			items = append(items, tc)
+		}
+	}
+	if item != nil {
+		tc := item.toNBT()
+		tc["Slot"] = nbt.NewByteTag(byte(slot))
+		items = append(items, tc)
+	}
+	tileEntity(inv.tc).setCompoundList(inv.name, items)
+}
+
+func (inv slotInventory) Items() (map[int]*ItemStack, error) {
+	items := make(map[int]*ItemStack)
+	var err error
+	for _, tc := range inv.list() {
+		s, ok := itemSlot(tc)
+		if !ok {
+			continue
+		}
+		it, itemErr := ItemStackFromNBT(tc)
+		if itemErr != nil {
+			err = fmt.Errorf("Could not read item in slot %d: %s", s, itemErr)
+			continue
+		}
+		items[s] = it
+	}
+	return items, err
+}
+
+// listInventory is an inventory stored as a list of items, where the position in the list is the slot
+// and empty compounds are empty slots (mob equipment).
+type listInventory struct {
+	tc   nbt.TagCompound
+	name string
+	size int
+}
+
+func (inv listInventory) Size() int { return inv.size }
+
+func (inv listInventory) Item(slot int) *ItemStack {
+	list := tileEntity(inv.tc).getCompoundList(inv.name)
+	if slot < 0 || slot >= len(list) {
+		return nil
+	}
+	it, _ := ItemStackFromNBT(list[slot])
+	return it
+}
+
+func (inv listInventory) SetItem(slot int, item *ItemStack) {
+	if slot < 0 || slot >= inv.size {
+		return
+	}
+
+	list := tileEntity(inv.tc).getCompoundList(inv.name)
+	for len(list) < inv.size {
+		list = append(list, make(nbt.TagCompound))
+	}
+	if item != nil {
+		list[slot] = item.toNBT()
+	} else {
+		list[slot] = make(nbt.TagCompound)
+	}
+	tileEntity(inv.tc).setCompoundList(inv.name, list)
+}
+
+func (inv listInventory) Items() (map[int]*ItemStack, error) {
+	items := make(map[int]*ItemStack)
+	var err error
+	for slot, tc := range tileEntity(inv.tc).getCompoundList(inv.name) {
+		if len(tc) == 0 {
+			continue // Empty slot
+		}
+		it, itemErr := ItemStackFromNBT(tc)
+		if itemErr != nil {
+			err = fmt.Errorf("Could not read item in slot %d: %s", slot, itemErr)
+			continue
+		}
+		items[slot] = it
+	}
+	return items, err
+}
+
+// Slots of player inventories.
+const (
+	PlayerSlotHotbar  = 0   // 0 - 8
+	PlayerSlotMain    = 9   // 9 - 35
+	PlayerSlotFeet    = 100 // Armor: 100 - 103
+	PlayerSlotHead    = 103
+	PlayerSlotOffHand = -106 // Minecraft 1.9+
+)
+
+// PlayerInventory returns the inventory of a player. player is the player compound
+// (the "Player" compound in the "Data" compound of level.dat or the root of a file in the playerdata folder).
+func PlayerInventory(player nbt.TagCompound) Inventory {
+	return slotInventory{player, "Inventory", 41}
+}
+
+// EnderChest returns the ender chest inventory of a player (see PlayerInventory).
+func EnderChest(player nbt.TagCompound) Inventory {
+	return slotInventory{player, "EnderItems", 27}
+}
@@ -0,0 +1,408 @@
+package mcmap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"sort"
+)
+
+// ItemStack is a stack of items in an inventory, a dropped item, an item frame, ...
+//
+// The common tags are available as fields, the other tags can be accessed in NBT.
+type ItemStack struct {
+	ID        string // Namespaced item ID, e.g. "minecraft:stone". Empty for items from versions before 1.8, see NumericID.
+	NumericID int16  // Item ID before Minecraft 1.8. Only used, if ID is empty.
+	Count     int
+	Damage    int // Damage of tools and armor. Before Minecraft 1.13 also the variant of other items (e.g. the wool color).
+
+	// The tag compound of the item (enchantments, display name, ...) or nil.
+	// Since Minecraft 1.13, the damage is stored in here, it is moved to the Damage field when reading the item.
+	Tag nbt.TagCompound
+
+	// The components of the item (e.g. "minecraft:enchantments"), which replace the tag compound since Minecraft 1.20.5.
+	// The damage is moved to the Damage field like for Tag.
+	Components nbt.TagCompound
+
+	// All tags of the item. The tags of the fields above are updated from the fields, when the item is written.
+	NBT nbt.TagCompound
+
+	flattened  bool // The item has the format of Minecraft 1.13+
+	components bool // The item has the format of Minecraft 1.20.5+
+}
+
+var (
+	InvalidItem = errors.New("Item has no id tag")
+)
+
+// NewItemStack creates an item stack in the format of the given data version. The id should be namespaced (e.g. "minecraft:stone").
+func NewItemStack(id string, count int, dataVersion int32) *ItemStack {
+	return &ItemStack{
+		ID:         id,
+		Count:      count,
+		flattened:  dataVersion >= dataVersionFlattening,
+		components: dataVersion >= dataVersionItemComponents,
+	}
+}
+
+// ItemStackFromNBT creates an item stack from its NBT data.
+func ItemStackFromNBT(tc nbt.TagCompound) (*ItemStack, error) {
+	it := &ItemStack{NBT: tc}
+
+	switch id := tc["id"]; id.Type {
+	case nbt.TAG_String:
+		it.ID = id.Payload.(string)
+	case nbt.TAG_Short:
+		it.NumericID = id.Payload.(int16)
+	case nbt.TAG_End:
+		return nil, InvalidItem
+	default:
+		return nil, errors.New("Could not read id tag: Not a TAG_String or TAG_Short")
+	}
+
+	count, err := tc.GetByte("Count")
+	if err == nbt.NotFound && it.ID != "" {
+		return it, it.readComponents(tc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Could not read Count tag: %s", err)
+	}
+	it.Count = int(int8(count))
+
+	var tagErr error
+	if it.Tag, tagErr = tc.GetCompound("tag"); optional(tagErr) != nil {
+		return nil, fmt.Errorf("Could not read tag tag: %s", tagErr)
+	}
+
+	// Before Minecraft 1.13 every item has a Damage tag, since 1.13 it is part of the tag compound.
+	if damage, err := tc.GetShort("Damage"); err == nil {
+		it.Damage = int(damage)
+	} else {
+		it.flattened = true
+		if damage, err := it.Tag.GetInt("Damage"); err == nil {
+			it.Damage = int(damage)
+			it.Tag = copyCompound(it.Tag)
+			delete(it.Tag, "Damage")
+		}
+	}
+
+	return it, nil
+}
+
+// readComponents reads the count and the components of an item in the format of Minecraft 1.20.5+.
+func (it *ItemStack) readComponents(tc nbt.TagCompound) error {
+	it.flattened, it.components = true, true
+
+	it.Count = 1
+	if count, err := tc.GetInt("count"); err == nil {
+		it.Count = int(count)
+	} else if err != nbt.NotFound {
+		return fmt.Errorf("Could not read count tag: %s", err)
+	}
+
+	var err error
+	if it.Components, err = tc.GetCompound("components"); optional(err) != nil {
+		return fmt.Errorf("Could not read components tag: %s", err)
+	}
+	if damage, err := it.Components.GetInt("minecraft:damage"); err == nil {
+		it.Damage = int(damage)
+		it.Components = copyCompound(it.Components)
+		delete(it.Components, "minecraft:damage")
+	}
+	return nil
+}
+
+// toNBT creates the NBT data of the item.
+func (it *ItemStack) toNBT() nbt.TagCompound {
+	tc := copyCompound(it.NBT)
+	if tc == nil {
+		tc = make(nbt.TagCompound)
+	}
+	if it.components {
+		return it.componentsToNBT(tc)
+	}
+
+	if it.ID != "" {
+		tc["id"] = nbt.Tag{nbt.TAG_String, it.ID}
+	} else {
+		tc["id"] = nbt.NewShortTag(it.NumericID)
+	}
+	tc["Count"] = nbt.NewByteTag(byte(it.Count))
+
+	tag := copyCompound(it.Tag)
+	if it.flattened {
+		delete(tc, "Damage")
+		if it.Damage != 0 {
+			if tag == nil {
+				tag = make(nbt.TagCompound)
+			}
+			tag["Damage"] = nbt.NewIntTag(int32(it.Damage))
+		}
+	} else {
+		tc["Damage"] = nbt.NewShortTag(int16(it.Damage))
+	}
+
+	if len(tag) > 0 {
+		tc["tag"] = nbt.Tag{nbt.TAG_Compound, tag}
+	} else {
+		delete(tc, "tag")
+	}
+
+	return tc
+}
+
+// componentsToNBT writes the fields of the item to tc in the format of Minecraft 1.20.5+.
+func (it *ItemStack) componentsToNBT(tc nbt.TagCompound) nbt.TagCompound {
+	for _, name := range []string{"Count", "Damage", "tag"} {
+		delete(tc, name)
+	}
+	tc["id"] = nbt.Tag{nbt.TAG_String, it.ID}
+	tc["count"] = nbt.NewIntTag(int32(it.Count))
+
+	components := copyCompound(it.Components)
+	if it.Damage != 0 {
+		if components == nil {
+			components = make(nbt.TagCompound)
+		}
+		components["minecraft:damage"] = nbt.NewIntTag(int32(it.Damage))
+	}
+	if len(components) > 0 {
+		tc["components"] = nbt.Tag{nbt.TAG_Compound, components}
+	} else {
+		delete(tc, "components")
+	}
+	return tc
+}
+
+// Copy returns a copy of the item stack, which shares no NBT data with it.
+func (it *ItemStack) Copy() *ItemStack {
+	cp := *it
+	cp.NBT = deepCopyCompound(it.NBT)
+	cp.Tag = deepCopyCompound(it.Tag)
+	cp.Components = deepCopyCompound(it.Components)
+	return &cp
+}
+
+// Is checks, if the item is of the given type. Namespaced and plain IDs are treated equally (e.g. "minecraft:stone" and "stone").
+func (it *ItemStack) Is(id string) bool {
+	return it.ID != "" && stripNamespace(it.ID) == stripNamespace(id)
+}
+
+func (it *ItemStack) setComponent(name string, tag nbt.Tag) {
+	if it.Components == nil {
+		it.Components = make(nbt.TagCompound)
+	}
+	it.Components[name] = tag
+}
+
+func (it *ItemStack) setTag(name string, tag nbt.Tag) {
+	if it.Tag == nil {
+		it.Tag = make(nbt.TagCompound)
+	}
+	it.Tag[name] = tag
+}
+
+// Enchantment is an enchantment of an item.
+type Enchantment struct {
+	ID        string // Namespaced enchantment ID (Minecraft 1.13+), e.g. "minecraft:sharpness".
+	NumericID int16  // Enchantment ID before Minecraft 1.13.
+	Level     int16
+}
+
+// enchantmentsTag returns the name of the enchantments list in the tag compound.
+func (it *ItemStack) enchantmentsTag() string {
+	if it.flattened {
+		return "Enchantments"
+	}
+	return "ench"
+}
+
+// Enchantments returns the enchantments of the item. For enchanted books see StoredEnchantments.
+func (it *ItemStack) Enchantments() []Enchantment { return it.enchantments(it.enchantmentsTag()) }
+
+func (it *ItemStack) SetEnchantments(ench []Enchantment) {
+	it.setEnchantments(it.enchantmentsTag(), ench)
+}
+
+// StoredEnchantments returns the enchantments stored in an enchanted book.
+func (it *ItemStack) StoredEnchantments() []Enchantment { return it.enchantments("StoredEnchantments") }
+
+func (it *ItemStack) SetStoredEnchantments(ench []Enchantment) {
+	it.setEnchantments("StoredEnchantments", ench)
+}
+
+// Components replacing the enchantment lists since Minecraft 1.20.5.
+var enchantmentComponents = map[string]string{
+	"Enchantments":       "minecraft:enchantments",
+	"StoredEnchantments": "minecraft:stored_enchantments",
+}
+
+func (it *ItemStack) enchantments(name string) []Enchantment {
+	if it.components {
+		return it.componentEnchantments(enchantmentComponents[name])
+	}
+
+	list, err := it.Tag.GetList(name)
+	if err != nil || list.Type != nbt.TAG_Compound {
+		return nil
+	}
+
+	ench := make([]Enchantment, 0, len(list.Elems))
+	for _, elem := range list.Elems {
+		tc := elem.(nbt.TagCompound)
+		var e Enchantment
+		switch id := tc["id"]; id.Type {
+		case nbt.TAG_String:
+			e.ID = id.Payload.(string)
+		case nbt.TAG_Short:
+			e.NumericID = id.Payload.(int16)
+		}
+		e.Level, _ = tc.GetShort("lvl")
+		ench = append(ench, e)
+	}
+	return ench
+}
+
+func (it *ItemStack) setEnchantments(name string, ench []Enchantment) {
+	if it.components {
+		it.setComponentEnchantments(enchantmentComponents[name], ench)
+		return
+	}
+
+	if len(ench) == 0 {
+		delete(it.Tag, name)
+		return
+	}
+
+	tcs := make([]nbt.TagCompound, len(ench))
+	for i, e := range ench {
+		tcs[i] = nbt.TagCompound{"lvl": nbt.NewShortTag(e.Level)}
+		if it.flattened {
+			tcs[i]["id"] = nbt.Tag{nbt.TAG_String, e.ID}
+		} else {
+			tcs[i]["id"] = nbt.NewShortTag(e.NumericID)
+		}
+	}
+	it.setTag(name, nbt.NewListTag(nbt.TAG_Compound, tcs))
+}
+
+func (it *ItemStack) componentEnchantments(name string) []Enchantment {
+	comp, err := it.Components.GetCompound(name)
+	if err != nil {
+		return nil
+	}
+	// Until Minecraft 1.21.4 the levels are stored in a levels compound.
+	if levels, err := comp.GetCompound("levels"); err == nil {
+		comp = levels
+	}
+
+	ench := make([]Enchantment, 0, len(comp))
+	for id, tag := range comp {
+		if tag.Type == nbt.TAG_Int {
+			ench = append(ench, Enchantment{ID: id, Level: int16(tag.Payload.(int32))})
+		}
+	}
+	sort.Slice(ench, func(i, j int) bool { return ench[i].ID < ench[j].ID })
+	return ench
+}
+
+func (it *ItemStack) setComponentEnchantments(name string, ench []Enchantment) {
+	if len(ench) == 0 {
+		delete(it.Components, name)
+		return
+	}
+
+	levels := make(nbt.TagCompound, len(ench))
+	for _, e := range ench {
+		levels[e.ID] = nbt.NewIntTag(int32(e.Level))
+	}
+
+	comp, _ := it.Components.GetCompound(name)
+	if _, err := comp.GetCompound("levels"); len(comp) > 0 && err != nil {
+		// The format of Minecraft 1.21.5+ without a levels compound.
+		it.setComponent(name, nbt.Tag{nbt.TAG_Compound, levels})
+		return
+	}
+	comp = copyCompound(comp)
+	if comp == nil {
+		comp = make(nbt.TagCompound)
+	}
+	comp["levels"] = nbt.Tag{nbt.TAG_Compound, levels}
+	it.setComponent(name, nbt.Tag{nbt.TAG_Compound, comp})
+}
+
+func (it *ItemStack) display() nbt.TagCompound {
+	display, _ := it.Tag.GetCompound("display")
+	return display
+}
+
+func (it *ItemStack) setDisplay(name string, tag nbt.Tag) {
+	display := it.display()
+	if display == nil {
+		display = make(nbt.TagCompound)
+		it.setTag("display", nbt.Tag{nbt.TAG_Compound, display})
+	}
+	display[name] = tag
+}
+
+func (it *ItemStack) deleteDisplay(name string) {
+	display := it.display()
+	delete(display, name)
+	if display != nil && len(display) == 0 {
+		delete(it.Tag, "display")
+	}
+}
+
+// DisplayName returns the custom name of the item (a JSON text component since Minecraft 1.13). Empty, if not set.
+func (it *ItemStack) DisplayName() string {
+	if it.components {
+		name, _ := it.Components.GetString("minecraft:custom_name")
+		return name
+	}
+	name, _ := it.display().GetString("Name")
+	return name
+}
+
+// SetDisplayName sets the custom name of the item. An empty name removes the custom name.
+func (it *ItemStack) SetDisplayName(name string) {
+	switch {
+	case it.components && name == "":
+		delete(it.Components, "minecraft:custom_name")
+	case it.components:
+		it.setComponent("minecraft:custom_name", nbt.Tag{nbt.TAG_String, name})
+	case name == "":
+		it.deleteDisplay("Name")
+	default:
+		it.setDisplay("Name", nbt.Tag{nbt.TAG_String, name})
+	}
+}
+
+// Lore returns the lines of the lore of the item (JSON text components since Minecraft 1.14).
+func (it *ItemStack) Lore() []string {
+	list, err := it.display().GetList("Lore")
+	if it.components {
+		list, err = it.Components.GetList("minecraft:lore")
+	}
+	if err != nil || list.Type != nbt.TAG_String {
+		return nil
+	}
+	lore := make([]string, len(list.Elems))
+	for i, elem := range list.Elems {
+		lore[i] = elem.(string)
+	}
+	return lore
+}
+
+// SetLore sets the lines of the lore. No lines remove the lore.
+func (it *ItemStack) SetLore(lore []string) {
+	switch {
+	case it.components && len(lore) == 0:
+		delete(it.Components, "minecraft:lore")
+	case it.components:
+		it.setComponent("minecraft:lore", nbt.NewListTag(nbt.TAG_String, lore))
+	case len(lore) == 0:
+		it.deleteDisplay("Lore")
+	default:
+		it.setDisplay("Lore", nbt.NewListTag(nbt.TAG_String, lore))
+	}
+}
@@ -0,0 +1,231 @@
+package mcmap
+
+// This file implements the recalculation of sky light and block light.
+//
+// Light spreads to the neighbouring blocks, losing at least one level per block (more, if the
+// neighbour has a higher opacity). Sky light that is not blocked by any opaque block keeps its full level
+// when going down.
+
+const maxLight = 15
+
+type lightPos struct {
+	x, y, z int // Global coordinates
+}
+
+// lightVolume is a set of chunks in which the light gets propagated.
+// Only the light of the target chunks will be changed, the other chunks only provide the light at the borders.
+type lightVolume struct {
+	targets map[XZPos]*Chunk
+	others  map[XZPos]*Chunk
+}
+
+// block returns the block at the global position and if it is in a target chunk.
+func (lv *lightVolume) block(x, y, z int) (blk *Block, target bool) {
+	pos := XZPos{x >> 4, z >> 4}
+	c, target := lv.targets[pos]
+	if !target {
+		if c = lv.others[pos]; c == nil {
+			return nil, false
+		}
+	}
+	return c.Block(x&0xf, y, z&0xf), target
+}
+
+func lightOpacity(blk *Block) byte {
+	if op := blk.ID.Properties().Opacity; op > 1 {
+		return op
+	}
+	return 1
+}
+
+var lightDirections = [6]lightPos{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+
+// propagate spreads the light from the queued positions. light selects the light value of a block.
+func (lv *lightVolume) propagate(queue []lightPos, light func(*Block) *byte) {
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		blk, _ := lv.block(p.x, p.y, p.z)
+		l := *light(blk)
+		if l <= 1 {
+			continue
+		}
+
+		for _, d := range lightDirections {
+			n := lightPos{p.x + d.x, p.y + d.y, p.z + d.z}
+			nblk, target := lv.block(n.x, n.y, n.z)
+			if nblk == nil || !target {
+				continue
+			}
+
+			op := lightOpacity(nblk)
+			if op >= l {
+				continue
+			}
+			if nl := light(nblk); *nl < l-op {
+				*nl = l - op
+				queue = append(queue, n)
+			}
+		}
+	}
+}
+
+// borderQueue returns the positions of the blocks in non-target chunks next to a target chunk, that can spread light.
+func (lv *lightVolume) borderQueue(light func(*Block) *byte) (queue []lightPos) {
+	for pos, c := range lv.others {
+		bx, bz := pos.X*ChunkSizeXZ, pos.Z*ChunkSizeXZ
+		c.Iter(func(x, y, z int, blk *Block) {
+			if *light(blk) <= 1 {
+				return
+			}
+			gx, gz := bx+x, bz+z
+			for _, d := range lightDirections {
+				if d.y != 0 {
+					continue
+				}
+				if _, ok := lv.targets[XZPos{(gx + d.x) >> 4, (gz + d.z) >> 4}]; ok {
+					queue = append(queue, lightPos{gx, y, gz})
+					return
+				}
+			}
+		})
+	}
+	return
+}
+
+func blockLightOf(blk *Block) *byte { return &blk.BlockLight }
+func skyLightOf(blk *Block) *byte   { return &blk.SkyLight }
+
+func (lv *lightVolume) recalc() {
+	var blockQueue, skyQueue []lightPos
+
+	for pos, c := range lv.targets {
+		bx, bz := pos.X*ChunkSizeXZ, pos.Z*ChunkSizeXZ
+
+		for z := 0; z < ChunkSizeXZ; z++ {
+			for x := 0; x < ChunkSizeXZ; x++ {
+				sky := byte(maxLight)
+				for y := c.MaxY(); y >= c.MinY(); y-- {
+					blk := c.Block(x, y, z)
+
+					if op := blk.ID.Properties().Opacity; op >= sky {
+						sky = 0
+					} else {
+						sky -= op
+					}
+					blk.SkyLight = sky
+					if sky > 1 {
+						skyQueue = append(skyQueue, lightPos{bx + x, y, bz + z})
+					}
+
+					blk.BlockLight = blk.ID.Properties().Emission
+					if blk.BlockLight > 1 {
+						blockQueue = append(blockQueue, lightPos{bx + x, y, bz + z})
+					}
+				}
+			}
+		}
+	}
+
+	lv.propagate(append(blockQueue, lv.borderQueue(blockLightOf)...), blockLightOf)
+	lv.propagate(append(skyQueue, lv.borderQueue(skyLightOf)...), skyLightOf)
+}
+
+// RecalcLight recalculates the sky light and block light of all blocks in the chunk and marks the chunk as modified.
+//
+// Light from neighbouring chunks is not taken into account, use Region.RecalcLight for this.
+func (c *Chunk) RecalcLight() {
+	lv := lightVolume{
+		targets: map[XZPos]*Chunk{XZPos{int(c.x), int(c.z)}: c},
+	}
+	lv.recalc()
+	c.MarkModified()
+}
+
+// chunkLoaded checks, if the chunk at x, z is currently loaded.
+func (reg *Region) chunkLoaded(x, z int) bool {
+	scx, scz, cx, cz := chunkToSuperchunk(x, z)
+	sc, ok := reg.superchunks[XZPos{scx, scz}]
+	if !ok {
+		return false
+	}
+	_, ok = sc.chunks[XZPos{cx, cz}]
+	return ok
+}
+
+// RecalcLight recalculates the light of the given chunks (by chunk coordinates). Light spreads between the chunks
+// and from the neighbouring chunks into the chunks. The light of the neighbouring chunks is not changed,
+// so you should include all chunks around your modifications.
+//
+// All chunks and their neighbours are loaded at the same time, so don't pass too many chunks at once.
+// Chunks that were not loaded before will be unloaded again, recalculated chunks are marked as modified.
+func (reg *Region) RecalcLight(chunks []XZPos) (err error) {
+	lv := lightVolume{
+		targets: make(map[XZPos]*Chunk),
+		others:  make(map[XZPos]*Chunk),
+	}
+
+	var loaded []*Chunk
+	defer func() {
+		for _, c := range loaded {
+			if uerr := c.MarkUnused(); uerr != nil && err == nil {
+				err = uerr
+			}
+		}
+	}()
+
+	load := func(pos XZPos) (*Chunk, error) {
+		wasLoaded := reg.chunkLoaded(pos.X, pos.Z)
+		c, err := reg.Chunk(pos.X, pos.Z)
+		switch err {
+		case nil:
+		case NotAvailable:
+			return nil, nil
+		default:
+			return nil, err
+		}
+		if !wasLoaded {
+			loaded = append(loaded, c)
+		}
+		return c, nil
+	}
+
+	for _, pos := range chunks {
+		c, err := load(pos)
+		if err != nil {
+			return err
+		}
+		if c != nil {
+			lv.targets[pos] = c
+		}
+	}
+
+	for pos := range lv.targets {
+		for dx := -1; dx <= 1; dx++ {
+			for dz := -1; dz <= 1; dz++ {
+				npos := XZPos{pos.X + dx, pos.Z + dz}
+				if _, ok := lv.targets[npos]; ok {
+					continue
+				}
+				if _, ok := lv.others[npos]; ok {
+					continue
+				}
+
+				c, err := load(npos)
+				if err != nil {
+					return err
+				}
+				if c != nil {
+					lv.others[npos] = c
+				}
+			}
+		}
+	}
+
+	lv.recalc()
+	for _, c := range lv.targets {
+		c.MarkModified()
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package mcmap
+
+import (
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"strconv"
+)
+
+// PointOfInterest is a block used by villagers and other mobs (beds, job site blocks, bells, nether portals, ...).
+// Since Minecraft 1.14, they are stored in the poi folder of a dimension.
+type PointOfInterest struct {
+	X, Y, Z     int    // Global position of the block.
+	Type        string // e.g. "minecraft:home"
+	FreeTickets int32  // Number of mobs that can still claim this point of interest.
+}
+
+func poiFromNBT(tc nbt.TagCompound) (PointOfInterest, error) {
+	var poi PointOfInterest
+
+	// The position is an int array since Minecraft 1.16 and a compound before.
+	if pos, err := tc.GetIntArray("pos"); err == nil && len(pos) == 3 {
+		poi.X, poi.Y, poi.Z = int(pos[0]), int(pos[1]), int(pos[2])
+	} else if pos, err := tc.GetCompound("pos"); err == nil {
+		x, _ := pos.GetInt("X")
+		y, _ := pos.GetInt("Y")
+		z, _ := pos.GetInt("Z")
+		poi.X, poi.Y, poi.Z = int(x), int(y), int(z)
+	} else {
+		return poi, fmt.Errorf("Could not read pos tag: %s", err)
+	}
+
+	var err error
+	if poi.Type, err = tc.GetString("type"); err != nil {
+		return poi, fmt.Errorf("Could not read type tag: %s", err)
+	}
+	if poi.FreeTickets, err = tc.GetInt("free_tickets"); optional(err) != nil {
+		return poi, fmt.Errorf("Could not read free_tickets tag: %s", err)
+	}
+	return poi, nil
+}
+
+// toNBT creates the NBT data of the point of interest in the format of the given data version.
+func (poi PointOfInterest) toNBT(dataVersion int32) nbt.TagCompound {
+	tc := nbt.TagCompound{
+		"type":         nbt.Tag{nbt.TAG_String, poi.Type},
+		"free_tickets": nbt.NewIntTag(poi.FreeTickets),
+	}
+
+	// Positions became int arrays in the same snapshot as UUIDs.
+	if dataVersion >= dataVersionIntArrayUUIDs {
+		tc["pos"] = nbt.NewIntArrayTag([]int32{int32(poi.X), int32(poi.Y), int32(poi.Z)})
+	} else {
+		tc["pos"] = nbt.Tag{nbt.TAG_Compound, nbt.TagCompound{
+			"X": nbt.NewIntTag(int32(poi.X)),
+			"Y": nbt.NewIntTag(int32(poi.Y)),
+			"Z": nbt.NewIntTag(int32(poi.Z)),
+		}}
+	}
+	return tc
+}
+
+// decodePOIs reads the root compound of a chunk in the poi folder.
+func (c *Chunk) decodePOIs(root nbt.TagCompound) error {
+	sections, err := root.GetCompound("Sections")
+	if err != nil {
+		return fmt.Errorf("Could not read Sections tag: %s", err)
+	}
+
+	c.POIs = nil
+	c.poiSections = make(map[int]nbt.TagCompound)
+	for key, tag := range sections {
+		y, err := strconv.Atoi(key)
+		if err != nil || tag.Type != nbt.TAG_Compound {
+			continue
+		}
+		section := tag.Payload.(nbt.TagCompound)
+
+		records, err := section.GetList("Records")
+		if optional(err) != nil {
+			return fmt.Errorf("Could not read Records tag: %s", err)
+		}
+		if records.Type == nbt.TAG_Compound {
+			for _, record := range records.Elems {
+				poi, err := poiFromNBT(record.(nbt.TagCompound))
+				if err != nil {
+					return err
+				}
+				c.POIs = append(c.POIs, poi)
+			}
+		}
+
+		extra := copyCompound(section)
+		delete(extra, "Records")
+		c.poiSections[y] = extra
+	}
+
+	return nil
+}
+
+// encodePOIs creates the root compound of the chunk in the poi folder. Returns nil, if the chunk has no points of interest.
+func (c *Chunk) encodePOIs() nbt.TagCompound {
+	if len(c.POIs) == 0 && len(c.poiSections) == 0 {
+		return nil
+	}
+
+	records := make(map[int][]nbt.TagCompound)
+	for y := range c.poiSections {
+		records[y] = nil
+	}
+	for _, poi := range c.POIs {
+		y := poi.Y >> 4
+		records[y] = append(records[y], poi.toNBT(c.dataVersion))
+	}
+
+	sections := make(nbt.TagCompound)
+	for y := range records {
+		section := copyCompound(c.poiSections[y])
+		if section == nil {
+			section = nbt.TagCompound{"Valid": nbt.NewByteTag(1)}
+		}
+		if len(records[y]) > 0 {
+			section["Records"] = nbt.NewListTag(nbt.TAG_Compound, records[y])
+		} else {
+			section["Records"] = nbt.NewListTag(nbt.TAG_Byte, []byte{})
+		}
+		sections[strconv.Itoa(y)] = nbt.Tag{nbt.TAG_Compound, section}
+	}
+
+	return nbt.TagCompound{
+		"DataVersion": nbt.NewIntTag(c.dataVersion),
+		"Sections":    nbt.Tag{nbt.TAG_Compound, sections},
+	}
+}
@@ -53,7 +53,7 @@ func extractCoord(tc nbt.TagCompound) (x, y, z int, err error) {
 	return
 }
 
-func (pc *preChunk) getLevelTag() (nbt.TagCompound, error) {
+func (pc *preChunk) getRootTag() (nbt.TagCompound, error) {
 	r := bytes.NewReader(pc.data)
 
 	var root nbt.Tag
@@ -75,55 +75,190 @@ func (pc *preChunk) getLevelTag() (nbt.TagCompound, error) {
 		return nil, errors.New("Root tag is not a TAG_Compound")
 	}
 
-	lvl, err := root.Payload.(nbt.TagCompound).GetCompound("Level")
-	if err != nil {
-		return nil, fmt.Errorf("Could not read Level tag: %s", err)
-	}
-
-	return lvl, nil
+	return root.Payload.(nbt.TagCompound), nil
 }
 
 func (pc *preChunk) toChunk(reg *Region) (*Chunk, error) {
 	c := Chunk{ts: pc.ts, reg: reg}
 
-	lvl, err := pc.getLevelTag()
+	root, err := pc.getRootTag()
+	if err != nil {
+		return nil, err
+	}
+
+	c.dataVersion, err = root.GetInt("DataVersion")
+	switch err {
+	case nil:
+	case nbt.NotFound:
+		c.dataVersion = 0
+	default:
+		return nil, fmt.Errorf("Could not read DataVersion tag: %s", err)
+	}
+
+	codec, err := ChunkCodecFor(c.dataVersion)
 	if err != nil {
 		return nil, err
 	}
+	if err := codec.Decode(root, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
 
+// Tags of the Level compound that are interpreted by readCommonTags and readLegacyTags.
+// All other tags are kept as they are.
+var legacyLevelTags = map[string]bool{
+	"xPos":             true,
+	"zPos":             true,
+	"LastUpdate":       true,
+	"InhabitedTime":    true,
+	"Entities":         true,
+	"TileEntities":     true,
+	"TileTicks":        true,
+	"TerrainPopulated": true,
+	"Biomes":           true,
+	"HeightMap":        true,
+	"Sections":         true,
+}
+
+// Tags of the sections that are interpreted by readLegacyTags.
+var legacySectionTags = map[string]bool{
+	"Y":          true,
+	"Blocks":     true,
+	"Add":        true,
+	"Data":       true,
+	"BlockLight": true,
+	"SkyLight":   true,
+}
+
+// unknownTags returns the tags of tc that are not in known.
+func unknownTags(tc nbt.TagCompound, known map[string]bool) nbt.TagCompound {
+	unknown := make(nbt.TagCompound)
+	for name, tag := range tc {
+		if !known[name] {
+			unknown[name] = tag
+		}
+	}
+	return unknown
+}
+
+// setSectionExtra remembers the unknown tags of the section at section Y y.
+func (c *Chunk) setSectionExtra(y int, section nbt.TagCompound, known map[string]bool) {
+	if extra := unknownTags(section, known); len(extra) > 0 {
+		if c.sectionExtra == nil {
+			c.sectionExtra = make(map[int]nbt.TagCompound)
+		}
+		c.sectionExtra[y] = extra
+	}
+}
+
+// newSection creates a section compound containing the unknown tags of the section at section Y y.
+func (c *Chunk) newSection(y int) nbt.TagCompound {
+	section := make(nbt.TagCompound)
+	for name, tag := range c.sectionExtra[y] {
+		section[name] = tag
+	}
+	section["Y"] = nbt.NewByteTag(byte(int8(y)))
+	return section
+}
+
+// decodeLevel reads the chunk from the Level compound (the root compound since 1.18).
+// Tags that are not interpreted by us are kept in c.extra.
+func (c *Chunk) decodeLevel(lvl nbt.TagCompound, flattened bool) (err error) {
+	if flattened {
+		c.extra = unknownTags(lvl, flattenedLevelTags)
+	} else {
+		c.extra = unknownTags(lvl, legacyLevelTags)
+	}
+
+	if err := c.readCommonTags(lvl); err != nil {
+		return err
+	}
+
+	if flattened {
+		err = c.readFlattenedTags(lvl)
+	} else {
+		err = c.readLegacyTags(lvl)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := c.readTileEntities(lvl); err != nil {
+		return err
+	}
+	return c.readTileTicks(lvl)
+}
+
+// readCommonTags reads the tags of the Level compound that are the same in all chunk formats.
+func (c *Chunk) readCommonTags(lvl nbt.TagCompound) (err error) {
 	c.x, err = lvl.GetInt("xPos")
 	if err != nil {
-		return nil, fmt.Errorf("Could not read xPos tag: %s", err)
+		return fmt.Errorf("Could not read xPos tag: %s", err)
 	}
 	c.z, err = lvl.GetInt("zPos")
 	if err != nil {
-		return nil, fmt.Errorf("Could not read zPos tag: %s", err)
+		return fmt.Errorf("Could not read zPos tag: %s", err)
 	}
 
 	c.lastUpdate, err = lvl.GetLong("LastUpdate")
 	if err != nil {
-		return nil, fmt.Errorf("Could not read LastUpdate tag: %s", err)
+		return fmt.Errorf("Could not read LastUpdate tag: %s", err)
 	}
 
-	populated, err := lvl.GetByte("TerrainPopulated")
+	c.inhabitedTime, err = lvl.GetLong("InhabitedTime")
 	switch err {
 	case nil:
 	case nbt.NotFound:
-		populated = 1
+		c.inhabitedTime = 0
 	default:
-		return nil, fmt.Errorf("Could not read TerrainPopulated tag: %s", err)
+		return fmt.Errorf("Could not read InhabitatedTime tag: %s", err)
 	}
-	c.populated = (populated == 1)
 
-	c.inhabitedTime, err = lvl.GetLong("InhabitedTime")
+	return c.readEntities(lvl)
+}
+
+// readEntities reads the Entities tag of tc (the Level compound or the root compound of a chunk in the entities folder).
+func (c *Chunk) readEntities(tc nbt.TagCompound) error {
+	ents, err := tc.GetList("Entities")
 	switch err {
 	case nil:
 	case nbt.NotFound:
-		c.inhabitedTime = 0
+		ents = nbt.TagList{Type: nbt.TAG_End}
 	default:
-		return nil, fmt.Errorf("Could not read InhabitatedTime tag: %s", err)
+		return fmt.Errorf("Could not read Entities tag: %s", err)
+	}
+	if ents.Type != nbt.TAG_Compound {
+		c.Entities = []*Entity{}
+	} else {
+		c.Entities = make([]*Entity, len(ents.Elems))
+		for i, _ent := range ents.Elems {
+			ent := _ent.(nbt.TagCompound)
+			switch c.Entities[i], err = EntityFromNBT(ent); err {
+			case nil:
+			case InvalidEntity:
+				c.Entities[i] = &Entity{NBT: ent, noPos: true} // Kept as it is
+			default:
+				return fmt.Errorf("Could not read Entities tag: %s", err)
+			}
+		}
 	}
 
+	return nil
+}
+
+func (c *Chunk) readLegacyTags(lvl nbt.TagCompound) error {
+	populated, err := lvl.GetByte("TerrainPopulated")
+	switch err {
+	case nil:
+	case nbt.NotFound:
+		populated = 1
+	default:
+		return fmt.Errorf("Could not read TerrainPopulated tag: %s", err)
+	}
+	c.populated = (populated == 1)
+
 	c.biomes = make([]Biome, ChunkRectXZ)
 	biomes, err := lvl.GetByteArray("Biomes")
 	switch err {
@@ -136,45 +271,29 @@ func (pc *preChunk) toChunk(reg *Region) (*Chunk, error) {
 			c.biomes[i] = BioUncalculated
 		}
 	default:
-		return nil, fmt.Errorf("Could not read Biomes tag: %s", err)
-	}
-
-	c.heightMap, err = lvl.GetIntArray("HeightMap")
-	if err != nil {
-		return nil, fmt.Errorf("Could not read HeightMap tag: %s", err)
+		return fmt.Errorf("Could not read Biomes tag: %s", err)
 	}
 
-	ents, err := lvl.GetList("Entities")
-	if err != nil {
-		return nil, fmt.Errorf("Could not read Entities tag: %s", err)
-	}
-	if ents.Type != nbt.TAG_Compound {
-		c.Entities = []nbt.TagCompound{}
-	} else {
-		c.Entities = make([]nbt.TagCompound, len(ents.Elems))
-		for i, ent := range ents.Elems {
-			c.Entities[i] = ent.(nbt.TagCompound)
-		}
-	}
+	// The height maps are recalculated, when needed (see Chunk.HeightOf), so the HeightMap tag is not read.
 
 	sections, err := lvl.GetList("Sections")
 	if (err != nil) || (sections.Type != nbt.TAG_Compound) {
-		return nil, fmt.Errorf("Could not read Section tag: %s", err)
+		return fmt.Errorf("Could not read Section tag: %s", err)
 	}
 
-	c.blocks = make([]Block, ChunkSize)
+	c.setRange(0, ChunkSizeY)
 	for _, _section := range sections.Elems {
 		section := _section.(nbt.TagCompound)
 
 		y, err := section.GetByte("Y")
 		if err != nil {
-			return nil, fmt.Errorf("Could not read Section -> Y tag: %s", err)
+			return fmt.Errorf("Could not read Section -> Y tag: %s", err)
 		}
 		off := int(y) * chunkSectionSize
 
 		blocks, err := section.GetByteArray("Blocks")
 		if err != nil {
-			return nil, fmt.Errorf("Could not read Section -> Blocks tag: %s", err)
+			return fmt.Errorf("Could not read Section -> Blocks tag: %s", err)
 		}
 		blocksAdd := make([]byte, chunkSectionSize)
 		add, err := section.GetByteArray("Add")
@@ -185,22 +304,24 @@ func (pc *preChunk) toChunk(reg *Region) (*Chunk, error) {
 			}
 		case nbt.NotFound:
 		default:
-			return nil, fmt.Errorf("Could not read Section -> Add tag: %s", err)
+			return fmt.Errorf("Could not read Section -> Add tag: %s", err)
 		}
 
 		blkData, err := section.GetByteArray("Data")
 		if err != nil {
-			return nil, fmt.Errorf("Could not read Section -> Data tag: %s", err)
+			return fmt.Errorf("Could not read Section -> Data tag: %s", err)
 		}
 		blockLight, err := section.GetByteArray("BlockLight")
 		if err != nil {
-			return nil, fmt.Errorf("Could not read Section -> BlockLight tag: %s", err)
+			return fmt.Errorf("Could not read Section -> BlockLight tag: %s", err)
 		}
 		skyLight, err := section.GetByteArray("SkyLight")
 		if err != nil {
-			return nil, fmt.Errorf("Could not read Section -> SkyLight tag: %s", err)
+			return fmt.Errorf("Could not read Section -> SkyLight tag: %s", err)
 		}
 
+		c.setSectionExtra(int(y), section, legacySectionTags)
+
 		for i := 0; i < chunkSectionSize; i++ {
 			c.blocks[off+i] = Block{
 				ID:         BlockID(uint16(blocks[i]) | (uint16(blocksAdd[i]) << 8)),
@@ -210,31 +331,82 @@ func (pc *preChunk) toChunk(reg *Region) (*Chunk, error) {
 		}
 	}
 
-	tileEnts, err := lvl.GetList("TileEntities")
-	if err != nil {
-		return nil, fmt.Errorf("Could not read TileEntities tag: %s", err)
+	return nil
+}
+
+// Tags renamed in Minecraft 1.18
+var renamedTags118 = map[string]string{
+	"Sections":     "sections",
+	"TileEntities": "block_entities",
+	"TileTicks":    "block_ticks",
+	"LiquidTicks":  "fluid_ticks",
+}
+
+// tagName returns the name of a tag in the format of the chunk.
+func (c *Chunk) tagName(name string) string {
+	if c.dataVersion >= dataVersionNoLevel {
+		if newName, ok := renamedTags118[name]; ok {
+			return newName
+		}
+	}
+	return name
+}
+
+func (c *Chunk) readTileEntities(lvl nbt.TagCompound) error {
+	tileEnts, err := lvl.GetList(c.tagName("TileEntities"))
+	switch err {
+	case nil:
+	case nbt.NotFound:
+		return nil
+	default:
+		return fmt.Errorf("Could not read %s tag: %s", c.tagName("TileEntities"), err)
 	}
 	if tileEnts.Type == nbt.TAG_Compound {
 		for _, _tEnt := range tileEnts.Elems {
 			tEnt := _tEnt.(nbt.TagCompound)
 			x, y, z, err := extractCoord(tEnt)
 			if err != nil {
-				return nil, fmt.Errorf("Could not Extract coords: %s", err)
+				return fmt.Errorf("Could not Extract coords: %s", err)
 			}
 
 			_, _, x, z = BlockToChunk(x, z)
 
-			c.blocks[calcBlockOffset(x, y, z)].TileEntity = tEnt
+			off := c.blockOffset(x, y, z)
+			if off < 0 {
+				return fmt.Errorf("Tile entity at y=%d is outside of the chunk", y)
+			}
+			c.blocks[off].TileEntity = tEnt
 		}
 	}
+	return nil
+}
+
+// readTileTicks reads the scheduled block updates and since Minecraft 1.13 the scheduled fluid updates.
+func (c *Chunk) readTileTicks(lvl nbt.TagCompound) error {
+	if err := c.readTickList(lvl, "TileTicks", func(off int, tick *TileTick) { c.blocks[off].Tick = tick }); err != nil {
+		return err
+	}
 
-	tileTicks, err := lvl.GetList("TileTicks")
+	if c.dataVersion < dataVersionFlattening {
+		return nil
+	}
+	c.fluidTicks = nil
+	return c.readTickList(lvl, "LiquidTicks", func(off int, tick *TileTick) {
+		if c.fluidTicks == nil {
+			c.fluidTicks = make(map[int]*TileTick)
+		}
+		c.fluidTicks[off] = tick
+	})
+}
+
+func (c *Chunk) readTickList(lvl nbt.TagCompound, name string, store func(off int, tick *TileTick)) error {
+	tileTicks, err := lvl.GetList(c.tagName(name))
 	if (err == nil) && (tileTicks.Type == nbt.TAG_Compound) {
 		for _, _tTick := range tileTicks.Elems {
 			tTick := _tTick.(nbt.TagCompound)
 			x, y, z, err := extractCoord(tTick)
 			if err != nil {
-				return nil, fmt.Errorf("Could not Extract coords: %s", err)
+				return fmt.Errorf("Could not Extract coords: %s", err)
 			}
 
 			_, _, x, z = BlockToChunk(x, z)
@@ -243,11 +415,24 @@ func (pc *preChunk) toChunk(reg *Region) (*Chunk, error) {
 			z %= ChunkSizeXZ
 
 			tick := TileTick{}
-			if tick.i, err = tTick.GetInt("i"); err != nil {
-				return nil, fmt.Errorf("Could not read i of a TileTag tag: %s", err)
+			switch i := tTick["i"]; i.Type {
+			case nbt.TAG_Int:
+				tick.i = i.Payload.(int32)
+			case nbt.TAG_String:
+				tick.name = i.Payload.(string)
+				var id BlockID
+				if c.dataVersion >= dataVersionFlattening {
+					id, _ = NewBlockState(tick.name, nil).Legacy()
+				} else {
+					id, _ = c.reg.Blocks().ID(tick.name)
+					c.stringTickIDs = true
+				}
+				tick.i = int32(id)
+			default:
+				return errors.New("Could not read i of a TileTag tag")
 			}
 			if tick.t, err = tTick.GetInt("t"); err != nil {
-				return nil, fmt.Errorf("Could not read t of a TileTag tag: %s", err)
+				return fmt.Errorf("Could not read t of a TileTag tag: %s", err)
 			}
 			switch tick.p, err = tTick.GetInt("p"); err {
 			case nil:
@@ -255,30 +440,108 @@ func (pc *preChunk) toChunk(reg *Region) (*Chunk, error) {
 			case nbt.NotFound:
 				tick.hasP = false
 			default:
-				return nil, fmt.Errorf("Could not read p of a TileTag tag: %s", err)
+				return fmt.Errorf("Could not read p of a TileTag tag: %s", err)
 			}
 
-			c.blocks[calcBlockOffset(x, y, z)].Tick = &tick
+			off := c.blockOffset(x, y, z)
+			if off < 0 {
+				return fmt.Errorf("Tile tick at y=%d is outside of the chunk", y)
+			}
+			store(off, &tick)
 		}
 	}
-
-	return &c, nil
+	return nil
 }
 
 func (c *Chunk) toPreChunk() (*preChunk, error) {
+	codec, err := ChunkCodecFor(c.dataVersion)
+	if err != nil {
+		return nil, err
+	}
+	root, err := codec.Encode(c)
+	if err != nil {
+		return nil, err
+	}
+	return newPreChunk(root, c.ts)
+}
+
+// newPreChunk compresses the root compound of a region file entry.
+func newPreChunk(root nbt.TagCompound, ts time.Time) (*preChunk, error) {
+	buf := new(bytes.Buffer)
+	if err := nbt.WriteZlibdNamedTag(buf, "", nbt.Tag{nbt.TAG_Compound, root}); err != nil {
+		return nil, err
+	}
+
+	return &preChunk{
+		ts:          ts,
+		data:        buf.Bytes(),
+		compression: compressZlib,
+	}, nil
+}
+
+// encodeLevel creates the Level compound (the root compound since 1.18).
+// The tags we don't interpret are merged with the tags created from the chunk data.
+func (c *Chunk) encodeLevel(flattened bool) nbt.TagCompound {
+	lvl := make(nbt.TagCompound)
+	for name, tag := range c.extra {
+		lvl[name] = tag
+	}
+	lvl["xPos"] = nbt.NewIntTag(c.x)
+	lvl["zPos"] = nbt.NewIntTag(c.z)
+	lvl["LastUpdate"] = nbt.NewLongTag(c.lastUpdate)
+	lvl["InhabitedTime"] = nbt.NewLongTag(c.inhabitedTime)
+	if c.dataVersion >= dataVersionNoLevel {
+		lvl["yPos"] = nbt.NewIntTag(int32(c.minY >> 4))
+	}
+
+	if flattened {
+		c.writeFlattenedTags(lvl)
+	} else {
+		c.writeLegacyTags(lvl)
+	}
+
+	if c.entitiesSeparate {
+		// Stored in the entities folder.
+	} else if len(c.Entities) > 0 {
+		lvl["Entities"] = c.entitiesTag()
+	} else if c.dataVersion < dataVersionNoLevel {
+		lvl["Entities"] = nbt.NewListTag(nbt.TAG_Byte, []byte{})
+	}
+
+	tileEnts, tileTicks := c.collectTileEntitiesAndTicks()
+	if len(tileEnts) > 0 {
+		lvl[c.tagName("TileEntities")] = nbt.NewListTag(nbt.TAG_Compound, tileEnts)
+	} else {
+		lvl[c.tagName("TileEntities")] = nbt.NewListTag(nbt.TAG_Byte, []byte{})
+	}
+	if len(tileTicks) > 0 {
+		lvl[c.tagName("TileTicks")] = nbt.NewListTag(nbt.TAG_Compound, tileTicks)
+	}
+	if flattened {
+		if fluidTicks := c.collectFluidTicks(); len(fluidTicks) > 0 {
+			lvl[c.tagName("LiquidTicks")] = nbt.NewListTag(nbt.TAG_Compound, fluidTicks)
+		}
+	}
+
+	return lvl
+}
+
+func (c *Chunk) entitiesTag() nbt.Tag {
+	ents := make([]nbt.TagCompound, len(c.Entities))
+	for i, ent := range c.Entities {
+		ents[i] = ent.toNBT(c.dataVersion)
+	}
+	return nbt.NewListTag(nbt.TAG_Compound, ents)
+}
+
+func (c *Chunk) writeLegacyTags(lvl nbt.TagCompound) {
 	terraPopulated := byte(0)
 	if c.populated {
 		terraPopulated = 1
 	}
-	lvl := nbt.TagCompound{
-		"xPos":             nbt.NewIntTag(c.x),
-		"zPos":             nbt.NewIntTag(c.z),
-		"LastUpdate":       nbt.NewLongTag(c.lastUpdate),
-		"TerrainPopulated": nbt.NewByteTag(terraPopulated),
-		"InhabitedTime":    nbt.NewLongTag(c.inhabitedTime),
-		"HeightMap":        nbt.NewIntArrayTag(c.heightMap),
-		"Entities":         nbt.Tag{nbt.TAG_Compound, c.Entities},
-	}
+	lvl["TerrainPopulated"] = nbt.NewByteTag(terraPopulated)
+	c.updateHeightMaps()
+	lvl["HeightMap"] = nbt.NewIntArrayTag(c.heightMaps[HeightMapLightBlocking])
 
 	hasBiomes := false
 	biomes := make([]byte, ChunkRectXZ)
@@ -286,6 +549,9 @@ func (c *Chunk) toPreChunk() (*preChunk, error) {
 		if bio != BioUncalculated {
 			hasBiomes = true
 		}
+		if bio > 0xff {
+			bio = BioUncalculated
+		}
 		biomes[i] = byte(bio)
 	}
 	if hasBiomes {
@@ -293,8 +559,6 @@ func (c *Chunk) toPreChunk() (*preChunk, error) {
 	}
 
 	sections := make([]nbt.TagCompound, 0)
-	tileEnts := make([]nbt.TagCompound, 0)
-	tileTicks := make([]nbt.TagCompound, 0)
 
 	for subchunk := 0; subchunk < 16; subchunk++ {
 		off := subchunk * chunkSectionSize
@@ -323,41 +587,14 @@ func (c *Chunk) toPreChunk() (*preChunk, error) {
 			setHalfbyte(data, i, blk.Data)
 			setHalfbyte(blockLight, i, blk.BlockLight)
 			setHalfbyte(skyLight, i, blk.SkyLight)
-
-			x, y, z := offsetToPos(i + off)
-			x, z = ChunkToBlock(int(c.x), int(c.z), x, z)
-
-			if (blk.TileEntity != nil) && (len(blk.TileEntity) > 0) {
-				// Fix coords
-				blk.TileEntity["x"] = nbt.NewIntTag(int32(x))
-				blk.TileEntity["y"] = nbt.NewIntTag(int32(y))
-				blk.TileEntity["z"] = nbt.NewIntTag(int32(z))
-				tileEnts = append(tileEnts, blk.TileEntity)
-			}
-
-			if blk.Tick != nil {
-				tileTick := nbt.TagCompound{
-					"x": nbt.NewIntTag(int32(x)),
-					"y": nbt.NewIntTag(int32(y)),
-					"z": nbt.NewIntTag(int32(z)),
-					"i": nbt.NewIntTag(blk.Tick.i),
-					"t": nbt.NewIntTag(blk.Tick.t),
-				}
-				if blk.Tick.hasP {
-					tileTick["p"] = nbt.NewIntTag(blk.Tick.p)
-				}
-				tileTicks = append(tileTicks, tileTick)
-			}
 		}
 
-		if !allAir {
-			comp := nbt.TagCompound{
-				"Y":          nbt.NewByteTag(byte(subchunk)),
-				"Blocks":     nbt.NewByteArrayTag(blocks),
-				"Data":       nbt.NewByteArrayTag(data),
-				"BlockLight": nbt.NewByteArrayTag(blockLight),
-				"SkyLight":   nbt.NewByteArrayTag(skyLight),
-			}
+		if !allAir || len(c.sectionExtra[subchunk]) > 0 {
+			comp := c.newSection(subchunk)
+			comp["Blocks"] = nbt.NewByteArrayTag(blocks)
+			comp["Data"] = nbt.NewByteArrayTag(data)
+			comp["BlockLight"] = nbt.NewByteArrayTag(blockLight)
+			comp["SkyLight"] = nbt.NewByteArrayTag(skyLight)
 			if !addEmpty {
 				comp["Add"] = nbt.NewByteArrayTag(add)
 			}
@@ -366,33 +603,59 @@ func (c *Chunk) toPreChunk() (*preChunk, error) {
 	}
 
 	lvl["Sections"] = nbt.NewListTag(nbt.TAG_Compound, sections)
+}
 
-	if len(c.Entities) > 0 {
-		lvl["Entities"] = nbt.NewListTag(nbt.TAG_Compound, c.Entities)
-	} else {
-		lvl["Entities"] = nbt.NewListTag(nbt.TAG_Byte, []byte{})
-	}
-	if len(tileEnts) > 0 {
-		lvl["TileEntities"] = nbt.NewListTag(nbt.TAG_Compound, tileEnts)
-	} else {
-		lvl["TileEntities"] = nbt.NewListTag(nbt.TAG_Byte, []byte{})
-	}
-	if len(tileTicks) > 0 {
-		lvl["TileTicks"] = nbt.NewListTag(nbt.TAG_Compound, tileTicks)
-	}
+// collectTileEntitiesAndTicks collects the tile entities and tile ticks of all blocks and fixes their coordinates.
+func (c *Chunk) collectTileEntitiesAndTicks() (tileEnts, tileTicks []nbt.TagCompound) {
+	for off, blk := range c.blocks {
+		if (blk.TileEntity == nil || len(blk.TileEntity) == 0) && blk.Tick == nil {
+			continue
+		}
 
-	root := nbt.Tag{nbt.TAG_Compound, nbt.TagCompound{
-		"Level": nbt.Tag{nbt.TAG_Compound, lvl},
-	}}
+		x, y, z := c.offsetToPos(off)
+		x, z = ChunkToBlock(int(c.x), int(c.z), x, z)
 
-	buf := new(bytes.Buffer)
-	if err := nbt.WriteZlibdNamedTag(buf, "", root); err != nil {
-		return nil, err
+		if (blk.TileEntity != nil) && (len(blk.TileEntity) > 0) {
+			// Fix coords
+			blk.TileEntity["x"] = nbt.NewIntTag(int32(x))
+			blk.TileEntity["y"] = nbt.NewIntTag(int32(y))
+			blk.TileEntity["z"] = nbt.NewIntTag(int32(z))
+			tileEnts = append(tileEnts, blk.TileEntity)
+		}
+
+		if blk.Tick != nil {
+			tileTicks = append(tileTicks, c.tickToNBT(blk.Tick, x, y, z))
+		}
 	}
+	return
+}
 
-	return &preChunk{
-		ts:          c.ts,
-		data:        buf.Bytes(),
-		compression: compressZlib,
-	}, nil
+// collectFluidTicks creates the list of scheduled fluid updates (1.13+).
+func (c *Chunk) collectFluidTicks() (fluidTicks []nbt.TagCompound) {
+	for off, tick := range c.fluidTicks {
+		x, y, z := c.offsetToPos(off)
+		x, z = ChunkToBlock(int(c.x), int(c.z), x, z)
+		fluidTicks = append(fluidTicks, c.tickToNBT(tick, x, y, z))
+	}
+	return
+}
+
+// tickToNBT creates the compound of a scheduled update at the global position x, y, z.
+func (c *Chunk) tickToNBT(tick *TileTick, x, y, z int) nbt.TagCompound {
+	tc := nbt.TagCompound{
+		"x": nbt.NewIntTag(int32(x)),
+		"y": nbt.NewIntTag(int32(y)),
+		"z": nbt.NewIntTag(int32(z)),
+		"i": nbt.NewIntTag(tick.i),
+		"t": nbt.NewIntTag(tick.t),
+	}
+	if tick.name != "" {
+		tc["i"] = nbt.Tag{nbt.TAG_String, tick.name}
+	} else if c.dataVersion >= dataVersionFlattening {
+		tc["i"] = nbt.Tag{nbt.TAG_String, Block{ID: BlockID(tick.i)}.BlockState().Name}
+	}
+	if tick.hasP {
+		tc["p"] = nbt.NewIntTag(tick.p)
+	}
+	return tc
 }
@@ -0,0 +1,412 @@
+package mcmap
+
+import (
+	"image/color"
+	"strings"
+	"sync"
+)
+
+// BlockProperties describes the physical properties of a block type.
+type BlockProperties struct {
+	Opacity     byte       // How much light gets absorbed by this block (0 - 15).
+	Emission    byte       // The light level this block emits (0 - 15).
+	Solid       bool       // Entities can not walk through this block.
+	FullCube    bool       // The block occupies the whole 1x1x1 space.
+	Liquid      bool       // The block is a liquid (water, lava).
+	Transparent bool       // Blocks behind this block can be (partially) seen.
+	Flammable   bool       // The block can catch fire.
+	Gravity     bool       // The block falls, if there is nothing below it.
+	MapColor    color.RGBA // Color used when rendering a map. Fully transparent for invisible blocks.
+}
+
+// UnknownBlockProperties are returned by BlockID.Properties for blocks without registered properties.
+// They describe a solid, opaque block, which is a good guess for most (modded) blocks.
+var UnknownBlockProperties = BlockProperties{
+	Opacity:  15,
+	Solid:    true,
+	FullCube: true,
+	MapColor: rgb(0xff00ff),
+}
+
+const maxBlockID = 0xfff
+
+var blockProperties [maxBlockID + 1]*BlockProperties
+
+// Properties of blocks without a numeric ID (see BlockState.Legacy), registered or derived from their name.
+var (
+	dynamicBlockProperties     = make(map[BlockID]*BlockProperties)
+	dynamicBlockPropertiesLock sync.RWMutex
+)
+
+// RegisterBlockProperties sets the properties of a block type, replacing the old ones.
+//
+// This is not safe for concurrent use with BlockID.Properties, so you should register your blocks at program start.
+func RegisterBlockProperties(id BlockID, props BlockProperties) {
+	if id > maxBlockID {
+		dynamicBlockPropertiesLock.Lock()
+		dynamicBlockProperties[id] = &props
+		dynamicBlockPropertiesLock.Unlock()
+		return
+	}
+	blockProperties[id] = &props
+}
+
+// Properties returns the properties of the block type. For unknown blocks UnknownBlockProperties are returned.
+// The properties of blocks without a numeric ID (added in Minecraft 1.13 and later) are derived from their name,
+// unrecognized ones are treated as transparent blocks.
+func (b BlockID) Properties() BlockProperties {
+	if b > maxBlockID {
+		return b.dynamicProperties()
+	}
+	if props := blockProperties[b]; props != nil {
+		return *props
+	}
+	return UnknownBlockProperties
+}
+
+func (b BlockID) dynamicProperties() BlockProperties {
+	dynamicBlockPropertiesLock.RLock()
+	props := dynamicBlockProperties[b]
+	dynamicBlockPropertiesLock.RUnlock()
+	if props != nil {
+		return *props
+	}
+
+	name, _ := DefaultBlocks.Name(b)
+	p := namedBlockProperties(name)
+
+	dynamicBlockPropertiesLock.Lock()
+	dynamicBlockProperties[b] = &p
+	dynamicBlockPropertiesLock.Unlock()
+	return p
+}
+
+// Name suffixes of full, opaque blocks added in Minecraft 1.13 and later.
+var fullCubeSuffixes = []string{
+	"_planks", "_log", "_wood", "_stem", "_hyphae", "_block", "_ore", "_bricks", "_tiles", "_concrete", "_concrete_powder",
+	"_terracotta", "_wool", "_copper", "_nylium", "deepslate", "tuff", "calcite", "blackstone", "basalt", "mud", "sculk",
+	"sandstone", "granite", "diorite", "andesite", "prismarine", "shroomlight", "froglight", "lodestone", "target", "beehive",
+	"bee_nest", "barrel", "smoker", "blast_furnace", "loom", "cartography_table", "fletching_table", "smithing_table",
+	"crying_obsidian", "respawn_anchor", "soul_soil", "rooted_dirt", "sculk_catalyst", "crafter", "_quartz", "_purpur",
+}
+
+// Name suffixes of blocks that are not full cubes, although they match fullCubeSuffixes.
+var partialBlockSuffixes = []string{
+	"_slab", "_stairs", "_wall", "_fence", "_fence_gate", "_door", "_trapdoor", "_pane", "_button", "_pressure_plate",
+	"honey_block", "_bars", "lantern", "chain", "candle", "bell", "lectern", "grindstone", "stonecutter", "campfire", "conduit",
+	"scaffolding", "_cauldron", "composter", "decorated_pot",
+}
+
+// Name suffixes of blocks, that are always waterlogged.
+var waterPlantSuffixes = []string{"seagrass", "kelp", "kelp_plant", "bubble_column", "_coral", "_coral_fan", "_coral_wall_fan"}
+
+// Light emitted by blocks added in Minecraft 1.13 and later.
+var namedBlockEmission = map[string]byte{
+	"lantern": 15, "soul_lantern": 10, "soul_torch": 10, "soul_wall_torch": 10, "soul_fire": 10, "campfire": 15,
+	"soul_campfire": 10, "shroomlight": 15, "ochre_froglight": 15, "verdant_froglight": 15, "pearlescent_froglight": 15,
+	"crying_obsidian": 10, "sea_pickle": 6, "glow_lichen": 7, "amethyst_cluster": 5, "large_amethyst_bud": 4,
+	"medium_amethyst_bud": 2, "small_amethyst_bud": 1, "conduit": 15, "lava_cauldron": 15, "sculk_catalyst": 6,
+	"light": 15, "end_rod": 14,
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// namedBlockProperties guesses the properties of a block without a numeric ID from its (namespaced) name.
+// Blocks that are not recognized as full or water filled blocks are transparent, so they don't shadow the blocks below.
+func namedBlockProperties(name string) BlockProperties {
+	name = stripNamespace(name)
+
+	props := BlockProperties{Transparent: true, MapColor: UnknownBlockProperties.MapColor}
+	switch {
+	case name == "":
+		return props
+	case hasAnySuffix(name, waterPlantSuffixes):
+		props = vanillaBlockProperties[BlkStationaryWater]
+	case strings.HasSuffix(name, "_leaves"):
+		props = vanillaBlockProperties[BlkLeaves]
+	case strings.HasPrefix(name, "attached_"):
+		// Stems of pumpkins and melons
+	case hasAnySuffix(name, partialBlockSuffixes):
+		props.Solid = true
+	case hasAnySuffix(name, fullCubeSuffixes):
+		props = BlockProperties{Opacity: 15, Solid: true, FullCube: true, MapColor: UnknownBlockProperties.MapColor}
+	}
+	props.Emission = namedBlockEmission[name]
+	return props
+}
+
+func rgb(c uint32) color.RGBA {
+	return color.RGBA{byte(c >> 16), byte(c >> 8), byte(c), 0xff}
+}
+
+func init() {
+	for id, props := range vanillaBlockProperties {
+		RegisterBlockProperties(id, props)
+	}
+}
+
+// Values from: http://minecraft.gamepedia.com/Opacity and http://minecraft.gamepedia.com/Light
+
+var vanillaBlockProperties = map[BlockID]BlockProperties{
+	BlkAir:                        {Transparent: true},
+	BlkStone:                      {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x666666)},
+	BlkGrassBlock:                 {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x00aa00)},
+	BlkDirt:                       {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x644804)},
+	BlkCobblestone:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x7a7a7a)},
+	BlkWoodPlanks:                 {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkSaplings:                   {Transparent: true, MapColor: rgb(0x57a100)},
+	BlkBedrock:                    {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x111111)},
+	BlkWater:                      {Opacity: 3, Liquid: true, Transparent: true, MapColor: rgb(0x0000ff)},
+	BlkStationaryWater:            {Opacity: 3, Liquid: true, Transparent: true, MapColor: rgb(0x0000ff)},
+	BlkLava:                       {Opacity: 15, Emission: 15, Liquid: true, Transparent: true, MapColor: rgb(0xff4400)},
+	BlkStationaryLava:             {Opacity: 15, Emission: 15, Liquid: true, Transparent: true, MapColor: rgb(0xff4400)},
+	BlkSand:                       {Opacity: 15, Solid: true, FullCube: true, Gravity: true, MapColor: rgb(0xf1ee85)},
+	BlkGravel:                     {Opacity: 15, Solid: true, FullCube: true, Gravity: true, MapColor: rgb(0x9ba3a9)},
+	BlkGoldOre:                    {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xffa200)},
+	BlkIronOre:                    {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe1e1e1)},
+	BlkCoalOre:                    {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x333333)},
+	BlkWood:                       {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkLeaves:                     {Opacity: 1, Solid: true, FullCube: true, Transparent: true, Flammable: true, MapColor: rgb(0x57a100)},
+	BlkSponge:                     {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe5e54c)},
+	BlkGlass:                      {Solid: true, FullCube: true, Transparent: true, MapColor: rgb(0xeeeeff)},
+	BlkLapisLazuliOre:             {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x3114e3)},
+	BlkLapisLazuliBlock:           {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x3114e3)},
+	BlkDispenser:                  {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x7a7a7a)},
+	BlkSandstone:                  {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xf1ee85)},
+	BlkNoteBlock:                  {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkBed:                        {Solid: true, Transparent: true, MapColor: rgb(0xa00000)},
+	BlkPoweredRail:                {Transparent: true, MapColor: rgb(0xff0000)},
+	BlkDetectorRail:               {Transparent: true, MapColor: rgb(0xff0000)},
+	BlkStickyPiston:               {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x91ba12)},
+	BlkCobweb:                     {Opacity: 1, Transparent: true, MapColor: rgb(0xdddddd)},
+	BlkGrass:                      {Transparent: true, Flammable: true, MapColor: rgb(0xa0f618)},
+	BlkDeadBush:                   {Transparent: true, Flammable: true, MapColor: rgb(0x946428)},
+	BlkPiston:                     {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xa4721c)},
+	BlkPistonExtension:            {Solid: true, Transparent: true, MapColor: rgb(0xa4721c)},
+	BlkWool:                       {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xffffff)},
+	BlkBlockMovedByPiston:         {Solid: true, Transparent: true, MapColor: rgb(0xa4721c)},
+	BlkDandelion:                  {Transparent: true, Flammable: true, MapColor: rgb(0xffff00)},
+	BlkFlower:                     {Transparent: true, Flammable: true, MapColor: rgb(0xff0000)},
+	BlkBrownMushroom:              {Emission: 1, Transparent: true, MapColor: rgb(0xb07859)},
+	BlkRedMushroom:                {Transparent: true, MapColor: rgb(0xdd0000)},
+	BlkBlockOfGold:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xffa200)},
+	BlkBlockOfIron:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe1e1e1)},
+	BlkDoubleSlabs:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x666666)},
+	BlkSlabs:                      {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0x666666)},
+	BlkBricks:                     {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xc42500)},
+	BlkTNT:                        {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa20022)},
+	BlkBookshelf:                  {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkMossStone:                  {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x589b71)},
+	BlkObsidian:                   {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x111144)},
+	BlkTorch:                      {Emission: 14, Transparent: true, MapColor: rgb(0xffcc00)},
+	BlkFire:                       {Emission: 15, Transparent: true, MapColor: rgb(0xffcc00)},
+	BlkMonsterSpawner:             {Solid: true, Transparent: true, MapColor: rgb(0x344e6a)},
+	BlkOakWoodStairs:              {Opacity: 15, Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkChest:                      {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkRedstoneWire:               {Transparent: true, MapColor: rgb(0xff0000)},
+	BlkDiamondOre:                 {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x00fff6)},
+	BlkBlockOfDiamond:             {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x00fff6)},
+	BlkCraftingTable:              {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkWheat:                      {Transparent: true, MapColor: rgb(0xe7ae00)},
+	BlkFarmland:                   {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0x644804)},
+	BlkFurnace:                    {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x7a7a7a)},
+	BlkBurningFurnace:             {Opacity: 15, Emission: 13, Solid: true, FullCube: true, MapColor: rgb(0x7a7a7a)},
+	BlkSignPost:                   {Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkWoodenDoor:                 {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkLadders:                    {Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkRail:                       {Transparent: true, MapColor: rgb(0xdbdbdb)},
+	BlkCobblestoneStairs:          {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0x7a7a7a)},
+	BlkWallSign:                   {Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkLever:                      {Transparent: true, MapColor: rgb(0xa4721c)},
+	BlkStonePressurePlate:         {Transparent: true, MapColor: rgb(0x666666)},
+	BlkIronDoor:                   {Solid: true, Transparent: true, MapColor: rgb(0xe1e1e1)},
+	BlkWoodenPressurePlate:        {Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkRedstoneOre:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xa00000)},
+	BlkGlowingRedstoneOre:         {Opacity: 15, Emission: 9, Solid: true, FullCube: true, MapColor: rgb(0xff0000)},
+	BlkRedstoneTorchInactive:      {Transparent: true, MapColor: rgb(0xff0000)},
+	BlkRedstoneTorchActive:        {Emission: 7, Transparent: true, MapColor: rgb(0xff0000)},
+	BlkStoneButton:                {Transparent: true, MapColor: rgb(0x666666)},
+	BlkSnow:                       {Solid: true, Transparent: true, MapColor: rgb(0xe5fffe)},
+	BlkIce:                        {Opacity: 3, Solid: true, FullCube: true, Transparent: true, MapColor: rgb(0x9fdcff)},
+	BlkSnowBlock:                  {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe5fffe)},
+	BlkCactus:                     {Solid: true, Transparent: true, MapColor: rgb(0x01bc3a)},
+	BlkClay:                       {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x767a82)},
+	BlkSugarCane:                  {Transparent: true, MapColor: rgb(0x12db50)},
+	BlkJukebox:                    {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkFence:                      {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkPumpkin:                    {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xff7000)},
+	BlkNetherrack:                 {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x851c2d)},
+	BlkSoulSand:                   {Solid: true, Transparent: true, MapColor: rgb(0x796a59)},
+	BlkGlowstone:                  {Opacity: 15, Emission: 15, Solid: true, FullCube: true, MapColor: rgb(0xffff00)},
+	BlkNetherPortal:               {Emission: 11, Transparent: true, MapColor: rgb(0xff00ff)},
+	BlkJackOLantern:               {Opacity: 15, Emission: 15, Solid: true, FullCube: true, MapColor: rgb(0xff7000)},
+	BlkCakeBlock:                  {Solid: true, Transparent: true, MapColor: rgb(0xf6f0e3)},
+	BlkRedstoneRepeaterInactive:   {Solid: true, Transparent: true, MapColor: rgb(0xff0000)},
+	BlkRedstoneRepeaterActive:     {Emission: 9, Solid: true, Transparent: true, MapColor: rgb(0xff0000)},
+	BlkStainedGlass:               {Solid: true, FullCube: true, Transparent: true, MapColor: rgb(0xeeeeff)},
+	BlkTrapdoor:                   {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkMonsterEgg:                 {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x666666)},
+	BlkStoneBricks:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x666666)},
+	BlkHugeBrownMushroom:          {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xb07859)},
+	BlkHugeRedMushroom:            {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xdd0000)},
+	BlkIronBars:                   {Solid: true, FullCube: true, Transparent: true, MapColor: rgb(0xe1e1e1)},
+	BlkGlassPane:                  {Solid: true, FullCube: true, Transparent: true, MapColor: rgb(0xeeeeff)},
+	BlkMelon:                      {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x9ac615)},
+	BlkPumpkinStem:                {Transparent: true, MapColor: rgb(0x50720d)},
+	BlkMelonStem:                  {Transparent: true, MapColor: rgb(0x50720d)},
+	BlkVines:                      {Transparent: true, Flammable: true, MapColor: rgb(0x50720d)},
+	BlkFenceGate:                  {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkBrickStairs:                {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0xc42500)},
+	BlkStoneBrickStairs:           {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0x666666)},
+	BlkMycelium:                   {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x7c668c)},
+	BlkLilyPad:                    {Solid: true, Transparent: true, MapColor: rgb(0x50720d)},
+	BlkNetherBrick:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xc42500)},
+	BlkNetherBrickFence:           {Solid: true, Transparent: true, MapColor: rgb(0xc42500)},
+	BlkNetherBrickStairs:          {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0xc42500)},
+	BlkNetherWart:                 {Transparent: true, MapColor: rgb(0x851c2d)},
+	BlkEnchantmentTable:           {Solid: true, Transparent: true, MapColor: rgb(0x222244)},
+	BlkBrewingStand:               {Emission: 1, Solid: true, Transparent: true, MapColor: rgb(0x666666)},
+	BlkCauldron:                   {Solid: true, Transparent: true, MapColor: rgb(0x666666)},
+	BlkEndPortal:                  {Emission: 15, Transparent: true, MapColor: rgb(0x000000)},
+	BlkEndPortalBlock:             {Emission: 1, Solid: true, Transparent: true, MapColor: rgb(0xe0dbce)},
+	BlkEndStone:                   {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe0dbce)},
+	BlkDragonEgg:                  {Emission: 1, Solid: true, Transparent: true, Gravity: true, MapColor: rgb(0x111144)},
+	BlkRedstoneLampInactive:       {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xffff00)},
+	BlkRedstoneLampActive:         {Opacity: 15, Emission: 15, Solid: true, FullCube: true, MapColor: rgb(0xffff00)},
+	BlkWoodenDoubleSlab:           {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkWoodenSlab:                 {Opacity: 15, Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkCocoa:                      {Solid: true, Transparent: true, MapColor: rgb(0x946428)},
+	BlkSandstoneStairs:            {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0xf1ee85)},
+	BlkEmeraldOre:                 {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x00c140)},
+	BlkEnderChest:                 {Emission: 7, Solid: true, Transparent: true, MapColor: rgb(0x222244)},
+	BlkTripwireHook:               {Transparent: true, MapColor: rgb(0xa4721c)},
+	BlkTripwire:                   {Transparent: true, MapColor: rgb(0xdddddd)},
+	BlkBlockOfEmerald:             {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x00c140)},
+	BlkSpruceWoodStairs:           {Opacity: 15, Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkBirchWoodStairs:            {Opacity: 15, Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkJungleWoodStairs:           {Opacity: 15, Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkCommandBlock:               {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe8ec78)},
+	BlkBeacon:                     {Emission: 15, Solid: true, Transparent: true, MapColor: rgb(0x00fff6)},
+	BlkCobblestoneWall:            {Solid: true, Transparent: true, MapColor: rgb(0x7a7a7a)},
+	BlkFlowerPot:                  {Solid: true, Transparent: true, MapColor: rgb(0x94492c)},
+	BlkCarrots:                    {Transparent: true, MapColor: rgb(0xff6000)},
+	BlkPotatoes:                   {Transparent: true, MapColor: rgb(0xc6cd0c)},
+	BlkWoodenButton:               {Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkMobHead:                    {Solid: true, Transparent: true, MapColor: rgb(0xdddddd)},
+	BlkAnvil:                      {Solid: true, Transparent: true, Gravity: true, MapColor: rgb(0x444444)},
+	BlkTrappedChest:               {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkWeightedPressurePlateLight: {Transparent: true, MapColor: rgb(0xffa200)},
+	BlkWeightedPressurePlateHeavy: {Transparent: true, MapColor: rgb(0xe1e1e1)},
+	BlkRedstoneComparatorInactive: {Solid: true, Transparent: true, MapColor: rgb(0xff0000)},
+	BlkRedstoneComparatorActive:   {Emission: 9, Solid: true, Transparent: true, MapColor: rgb(0xff0000)},
+	BlkDaylightSensor:             {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkBlockOfRedstone:            {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xff0000)},
+	BlkNetherQuartzOre:            {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe7e7e7)},
+	BlkHopper:                     {Solid: true, Transparent: true, MapColor: rgb(0x444444)},
+	BlkBlockOfQuartz:              {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe7e7e7)},
+	BlkQuartzStairs:               {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0xe7e7e7)},
+	BlkActivatorRail:              {Transparent: true, MapColor: rgb(0xff0000)},
+	BlkDropper:                    {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x444444)},
+	BlkStainedClay:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x767a82)},
+	BlkStainedGlassPane:           {Solid: true, FullCube: true, Transparent: true, MapColor: rgb(0xeeeeff)},
+	BlkLeaves2:                    {Opacity: 1, Solid: true, FullCube: true, Transparent: true, Flammable: true, MapColor: rgb(0x57a100)},
+	BlkWood2:                      {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkAcaciaWoodStairs:           {Opacity: 15, Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkDarkOakWoodStairs:          {Opacity: 15, Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0x4c3317)},
+	BlkSlimeBlock:                 {Solid: true, FullCube: true, Transparent: true, MapColor: rgb(0x7fcc63)},
+	BlkBarrier:                    {Solid: true, FullCube: true, Transparent: true},
+	BlkIronTrapdoor:               {Solid: true, Transparent: true, MapColor: rgb(0xe1e1e1)},
+	BlkPrismarine:                 {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x4c7f99)},
+	BlkSeaLantern:                 {Opacity: 15, Emission: 15, Solid: true, FullCube: true, MapColor: rgb(0xdcf2ef)},
+	BlkHayBlock:                   {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0xe7ae00)},
+	BlkCarpet:                     {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xffffff)},
+	BlkHardenedClay:               {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x767a82)},
+	BlkBlockOfCoal:                {Opacity: 15, Solid: true, FullCube: true, Flammable: true, MapColor: rgb(0x333333)},
+	BlkPackedIce:                  {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x9fdcff)},
+	BlkLargeFlower:                {Transparent: true, Flammable: true, MapColor: rgb(0xa0f618)},
+	BlkStandingBanner:             {Transparent: true, Flammable: true, MapColor: rgb(0xffffff)},
+	BlkWallBanner:                 {Transparent: true, Flammable: true, MapColor: rgb(0xffffff)},
+	BlkInvertedDaylightSensor:     {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkRedSandstone:               {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xd87f33)},
+	BlkRedSandstoneStairs:         {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0xd87f33)},
+	BlkDoubleRedSandstoneSlab:     {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xd87f33)},
+	BlkRedSandstoneSlab:           {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0xd87f33)},
+	BlkSpruceFenceGate:            {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkBirchFenceGate:             {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkJungleFenceGate:            {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkDarkOakFenceGate:           {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkAcaciaFenceGate:            {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkSpruceFence:                {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkBirchFence:                 {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkJungleFence:                {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkDarkOakFence:               {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkAcaciaFence:                {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkSpruceDoor:                 {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkBirchDoor:                  {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkJungleDoor:                 {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkAcaciaDoor:                 {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkDarkOakDoor:                {Solid: true, Transparent: true, Flammable: true, MapColor: rgb(0xa4721c)},
+	BlkEndRod:                     {Emission: 14, Solid: true, Transparent: true, MapColor: rgb(0xffffff)},
+	BlkChorusPlant:                {Solid: true, Transparent: true, MapColor: rgb(0x7f3fb2)},
+	BlkChorusFlower:               {Solid: true, Transparent: true, MapColor: rgb(0x7f3fb2)},
+	BlkPurpurBlock:                {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xb27fd8)},
+	BlkPurpurPillar:               {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xb27fd8)},
+	BlkPurpurStairs:               {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0xb27fd8)},
+	BlkPurpurDoubleSlab:           {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xb27fd8)},
+	BlkPurpurSlab:                 {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0xb27fd8)},
+	BlkEndStoneBricks:             {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe0dbce)},
+	BlkBeetroots:                  {Transparent: true, MapColor: rgb(0x7a0000)},
+	BlkGrassPath:                  {Opacity: 15, Solid: true, Transparent: true, MapColor: rgb(0x946428)},
+	BlkEndGateway:                 {Emission: 15, Transparent: true, MapColor: rgb(0x000000)},
+	BlkRepeatingCommandBlock:      {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x7f3fb2)},
+	BlkChainCommandBlock:          {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x667f33)},
+	BlkFrostedIce:                 {Opacity: 3, Solid: true, FullCube: true, Transparent: true, MapColor: rgb(0x9fdcff)},
+	BlkMagmaBlock:                 {Opacity: 15, Emission: 3, Solid: true, FullCube: true, MapColor: rgb(0x701c0a)},
+	BlkNetherWartBlock:            {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x991818)},
+	BlkRedNetherBrick:             {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x701c0a)},
+	BlkBoneBlock:                  {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xf7e9a3)},
+	BlkStructureVoid:              {Transparent: true},
+	BlkObserver:                   {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x7a7a7a)},
+	BlkWhiteShulkerBox:            {Solid: true, Transparent: true, MapColor: rgb(0xcfd5d6)},
+	BlkOrangeShulkerBox:           {Solid: true, Transparent: true, MapColor: rgb(0xe06100)},
+	BlkMagentaShulkerBox:          {Solid: true, Transparent: true, MapColor: rgb(0xa9309f)},
+	BlkLightBlueShulkerBox:        {Solid: true, Transparent: true, MapColor: rgb(0x2389c6)},
+	BlkYellowShulkerBox:           {Solid: true, Transparent: true, MapColor: rgb(0xf0af15)},
+	BlkLimeShulkerBox:             {Solid: true, Transparent: true, MapColor: rgb(0x5ea818)},
+	BlkPinkShulkerBox:             {Solid: true, Transparent: true, MapColor: rgb(0xd5658e)},
+	BlkGrayShulkerBox:             {Solid: true, Transparent: true, MapColor: rgb(0x373a3e)},
+	BlkLightGrayShulkerBox:        {Solid: true, Transparent: true, MapColor: rgb(0x7d7d73)},
+	BlkCyanShulkerBox:             {Solid: true, Transparent: true, MapColor: rgb(0x157788)},
+	BlkPurpleShulkerBox:           {Solid: true, Transparent: true, MapColor: rgb(0x64209c)},
+	BlkBlueShulkerBox:             {Solid: true, Transparent: true, MapColor: rgb(0x2c2e8f)},
+	BlkBrownShulkerBox:            {Solid: true, Transparent: true, MapColor: rgb(0x603b1f)},
+	BlkGreenShulkerBox:            {Solid: true, Transparent: true, MapColor: rgb(0x495b24)},
+	BlkRedShulkerBox:              {Solid: true, Transparent: true, MapColor: rgb(0x8e2020)},
+	BlkBlackShulkerBox:            {Solid: true, Transparent: true, MapColor: rgb(0x080a0f)},
+	BlkWhiteGlazedTerracotta:      {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xcfd5d6)},
+	BlkOrangeGlazedTerracotta:     {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xe06100)},
+	BlkMagentaGlazedTerracotta:    {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xa9309f)},
+	BlkLightBlueGlazedTerracotta:  {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x2389c6)},
+	BlkYellowGlazedTerracotta:     {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xf0af15)},
+	BlkLimeGlazedTerracotta:       {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x5ea818)},
+	BlkPinkGlazedTerracotta:       {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xd5658e)},
+	BlkGrayGlazedTerracotta:       {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x373a3e)},
+	BlkLightGrayGlazedTerracotta:  {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x7d7d73)},
+	BlkCyanGlazedTerracotta:       {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x157788)},
+	BlkPurpleGlazedTerracotta:     {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x64209c)},
+	BlkBlueGlazedTerracotta:       {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x2c2e8f)},
+	BlkBrownGlazedTerracotta:      {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x603b1f)},
+	BlkGreenGlazedTerracotta:      {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x495b24)},
+	BlkRedGlazedTerracotta:        {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x8e2020)},
+	BlkBlackGlazedTerracotta:      {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x080a0f)},
+	BlkConcrete:                   {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0xcfd5d6)},
+	BlkConcretePowder:             {Opacity: 15, Solid: true, FullCube: true, Gravity: true, MapColor: rgb(0xe1e3e4)},
+	BlkStructureBlock:             {Opacity: 15, Solid: true, FullCube: true, MapColor: rgb(0x7a6a7a)},
+}
@@ -25,6 +25,14 @@ type Region struct {
 	autosave         bool
 	superchunksAvail map[XZPos]bool
 	superchunks      map[XZPos]*superchunk
+	blocks           *BlockRegistry
+
+	// Region folders with the entities (1.17+) and points of interest (1.14+) of the chunks, nil if not used.
+	entities, poi *chunkStore
+
+	// Format of new chunks (see SetChunkFormat)
+	dataVersion int32
+	minY, sizeY int
 }
 
 var mcaRegex = regexp.MustCompile(`^r\.([0-9-]+)\.([0-9-]+)\.mca$`)
@@ -182,6 +190,9 @@ func (reg *Region) cleanSuperchunks(forceSave bool) error {
 
 	for scPos, _ := range del {
 		delete(reg.superchunks, scPos)
+		if err := reg.flushStores(scPos); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -203,6 +214,9 @@ func (sc *superchunk) loadChunk(reg *Region, rx, rz int) (*Chunk, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := reg.loadSeparateData(chunk); err != nil {
+		return nil, err
+	}
 	sc.chunks[cPos] = chunk
 	return chunk, nil
 }
@@ -250,12 +264,22 @@ func (reg *Region) unloadChunk(x, z int) error {
 	if chunk.deleted {
 		delete(sc.preChunks, cPos)
 		sc.modified = true
+		if err := reg.deleteSeparateData(x, z); err != nil {
+			return err
+		}
 	} else if chunk.modified {
+		if err := reg.rehomeEntities(chunk); err != nil {
+			return err
+		}
+
 		pc, err := chunk.toPreChunk()
 		if err != nil {
 			return err
 		}
 		sc.preChunks[cPos] = pc
+		if err := reg.saveSeparateData(chunk); err != nil {
+			return err
+		}
 
 		chunk.modified = false
 		sc.modified = true
@@ -266,6 +290,56 @@ func (reg *Region) unloadChunk(x, z int) error {
 	return nil
 }
 
+// rehomeEntities moves the entities of c, whose positions are in another chunk, to that chunk.
+// The other chunk gets loaded, if needed. If it does not exist, the entity stays in c.
+func (reg *Region) rehomeEntities(c *Chunk) error {
+	keep := make([]*Entity, 0, len(c.Entities))
+	var moved []*Entity
+	for _, ent := range c.Entities {
+		x, _, z := ent.BlockPos()
+		if cx, cz, _, _ := BlockToChunk(x, z); ent.noPos || (cx == int(c.x) && cz == int(c.z)) {
+			keep = append(keep, ent)
+		} else {
+			moved = append(moved, ent)
+		}
+	}
+	if len(moved) == 0 {
+		return nil
+	}
+
+	var unused []*Chunk
+	for _, ent := range moved {
+		x, _, z := ent.BlockPos()
+		cx, cz, _, _ := BlockToChunk(x, z)
+
+		wasLoaded := reg.chunkLoaded(cx, cz)
+		target, err := reg.Chunk(cx, cz)
+		switch err {
+		case nil:
+		case NotAvailable:
+			keep = append(keep, ent)
+			continue
+		default:
+			return err
+		}
+
+		target.Entities = append(target.Entities, ent)
+		target.MarkModified()
+		if !wasLoaded {
+			unused = append(unused, target)
+		}
+	}
+
+	// Unloading the targets can move their entities to c, so c.Entities must be final before.
+	c.Entities = keep
+	for _, target := range unused {
+		if err := target.MarkUnused(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AllChunks returns a channel that will give you the positions of all possibly available chunks in an efficient order.
 //
 // Note the "possibly available", you still have to check, if the chunk could actually be loaded.
@@ -337,6 +411,53 @@ func (reg *Region) NewChunk(cx, cz int) (*Chunk, error) {
 	return chunk, nil
 }
 
+// SetChunkFormat sets the format of chunks created by NewChunk. dataVersion is the data version of the
+// Minecraft version (0 for versions before 1.9), minY and height are the vertical range of the chunks and must be multiples of 16.
+// Since Minecraft 1.18, the overworld ranges from -64 to 319, other dimensions from 0 to 255.
+//
+// By default, new chunks have the format used before Minecraft 1.13 with a range from 0 to 255.
+// World.Region sets the format based on the level.dat.
+func (reg *Region) SetChunkFormat(dataVersion int32, minY, height int) {
+	reg.dataVersion = dataVersion
+	reg.minY = minY
+	reg.sizeY = height
+}
+
+// Blocks returns the block registry used for this region. Unless the region was opened with World.Region, this is DefaultBlocks.
+func (reg *Region) Blocks() *BlockRegistry {
+	if reg.blocks == nil {
+		return DefaultBlocks
+	}
+	return reg.blocks
+}
+
+// CopyChunk copies the chunk src (which can belong to another region) to the same position in this region.
+// An already existing chunk will be overwritten. If remap is not nil, the block IDs will be remapped
+// (use NewBlockRemap to copy chunks between worlds with different block IDs).
+//
+// The returned chunk is marked as modified. You have to call MarkUnused on it, if you don't need it any longer.
+func (reg *Region) CopyChunk(src *Chunk, remap BlockRemap) (*Chunk, error) {
+	cx, cz := src.Coords()
+
+	chunk, err := reg.Chunk(int(cx), int(cz))
+	switch err {
+	case nil:
+	case NotAvailable:
+		if chunk, err = reg.NewChunk(int(cx), int(cz)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if chunk != src {
+		chunk.copyFrom(src)
+	}
+	remap.Chunk(chunk)
+	chunk.MarkModified()
+	return chunk, nil
+}
+
 // Save saves modified and unused chunks.
 func (reg *Region) Save() error {
 	return reg.cleanSuperchunks(true)
@@ -0,0 +1,498 @@
+package mcmap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BlockRegistry maps numeric block IDs (and optionally data values) to namespaced names like "minecraft:stone" and back.
+//
+// It is safe for concurrent use. New blocks (e.g. from mods) can be registered at any time.
+type BlockRegistry struct {
+	mu         sync.RWMutex
+	names      map[BlockID]string
+	ids        map[string]BlockID
+	variants   map[blockVariant]string
+	variantIDs map[string]blockVariant
+}
+
+type blockVariant struct {
+	id   BlockID
+	data byte
+}
+
+// NewBlockRegistry creates a new, empty registry.
+func NewBlockRegistry() *BlockRegistry {
+	return &BlockRegistry{
+		names:      make(map[BlockID]string),
+		ids:        make(map[string]BlockID),
+		variants:   make(map[blockVariant]string),
+		variantIDs: make(map[string]blockVariant),
+	}
+}
+
+// NormalizeBlockName converts a block name to its canonical form: lowercase and with a namespace ("stone" becomes "minecraft:stone").
+func NormalizeBlockName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+	return name
+}
+
+// Register registers the name for a block ID. An already registered name for that ID will be replaced.
+func (r *BlockRegistry) Register(id BlockID, name string) {
+	name = NormalizeBlockName(name)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.names[id]; ok {
+		delete(r.ids, old)
+	}
+	if oldID, ok := r.ids[name]; ok {
+		delete(r.names, oldID)
+	}
+	r.names[id] = name
+	r.ids[name] = id
+}
+
+// RegisterVariant registers a name for a specific combination of block ID and data value (e.g. a modded block using the data value as subtype).
+func (r *BlockRegistry) RegisterVariant(id BlockID, data byte, name string) {
+	name = NormalizeBlockName(name)
+	v := blockVariant{id, data & 0xf}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.variants[v]; ok {
+		delete(r.variantIDs, old)
+	}
+	if oldV, ok := r.variantIDs[name]; ok {
+		delete(r.variants, oldV)
+	}
+	r.variants[v] = name
+	r.variantIDs[name] = v
+}
+
+// Unregister removes a block ID and all of its variants from the registry.
+func (r *BlockRegistry) Unregister(id BlockID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if name, ok := r.names[id]; ok {
+		delete(r.ids, name)
+		delete(r.names, id)
+	}
+	for v, name := range r.variants {
+		if v.id == id {
+			delete(r.variants, v)
+			delete(r.variantIDs, name)
+		}
+	}
+}
+
+// Name returns the registered name of a block ID.
+func (r *BlockRegistry) Name(id BlockID) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name, ok := r.names[id]
+	return name, ok
+}
+
+// ID looks up the block ID of a name. The namespace can be omitted for "minecraft:" blocks.
+func (r *BlockRegistry) ID(name string) (BlockID, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.ids[NormalizeBlockName(name)]
+	return id, ok
+}
+
+// IDs returns all registered block IDs together with their names.
+func (r *BlockRegistry) IDs() map[BlockID]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rv := make(map[BlockID]string, len(r.names))
+	for id, name := range r.names {
+		rv[id] = name
+	}
+	return rv
+}
+
+// Clone creates an independent copy of the registry.
+func (r *BlockRegistry) Clone() *BlockRegistry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rv := NewBlockRegistry()
+	for id, name := range r.names {
+		rv.names[id] = name
+		rv.ids[name] = id
+	}
+	for v, name := range r.variants {
+		rv.variants[v] = name
+		rv.variantIDs[name] = v
+	}
+	return rv
+}
+
+// FormatBlockID formats a block ID as its name. Unregistered IDs are formatted as decimal numbers.
+func (r *BlockRegistry) FormatBlockID(id BlockID) string {
+	if name, ok := r.Name(id); ok {
+		return name
+	}
+	return strconv.Itoa(int(id))
+}
+
+// FormatBlock formats a block ID and data value. If a variant name is registered, it will be used.
+// Otherwise the result looks like "minecraft:wool:14". The data value is omitted, if it is 0.
+func (r *BlockRegistry) FormatBlock(id BlockID, data byte) string {
+	data &= 0xf
+
+	r.mu.RLock()
+	name, ok := r.variants[blockVariant{id, data}]
+	r.mu.RUnlock()
+	if ok {
+		return name
+	}
+
+	name = r.FormatBlockID(id)
+	if data != 0 {
+		name += ":" + strconv.Itoa(int(data))
+	}
+	return name
+}
+
+// ParseBlockID parses a block name (e.g. "minecraft:stone" or "stone") or a decimal block ID.
+func (r *BlockRegistry) ParseBlockID(s string) (BlockID, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseUint(s, 10, 16); err == nil {
+		return BlockID(n), nil
+	}
+
+	if id, ok := r.ID(s); ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf("Unknown block %q", s)
+}
+
+// ParseBlock parses a block name with an optional data value. Accepted are registered variant names and
+// everything ParseBlockID accepts, optionally followed by ":" and a data value ("minecraft:wool:14", "wool:14", "35:14").
+func (r *BlockRegistry) ParseBlock(s string) (id BlockID, data byte, err error) {
+	s = strings.TrimSpace(s)
+
+	r.mu.RLock()
+	v, ok := r.variantIDs[NormalizeBlockName(s)]
+	r.mu.RUnlock()
+	if ok {
+		return v.id, v.data, nil
+	}
+
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		if n, err := strconv.ParseUint(s[i+1:], 10, 8); err == nil {
+			if n > 0xf {
+				return 0, 0, fmt.Errorf("Data value of %q out of range", s)
+			}
+			id, err := r.ParseBlockID(s[:i])
+			return id, byte(n), err
+		}
+	}
+
+	id, err = r.ParseBlockID(s)
+	return id, 0, err
+}
+
+// DefaultBlocks is the registry used by BlockID.Name, Block.Name, ParseBlockID and ParseBlock.
+// It contains the vanilla blocks, you can register additional blocks here.
+var DefaultBlocks = newDefaultBlockRegistry()
+
+func newDefaultBlockRegistry() *BlockRegistry {
+	r := NewBlockRegistry()
+	for id, name := range vanillaBlockNames {
+		r.Register(id, name)
+	}
+	return r
+}
+
+// Name returns the namespaced name of the block ID (e.g. "minecraft:stone"), as registered in DefaultBlocks.
+func (b BlockID) Name() string { return DefaultBlocks.FormatBlockID(b) }
+
+// Name returns the namespaced name of the block including its data value (see BlockRegistry.FormatBlock), as registered in DefaultBlocks.
+func (b Block) Name() string { return DefaultBlocks.FormatBlock(b.ID, b.Data) }
+
+// ParseBlockID parses a block ID using DefaultBlocks. See BlockRegistry.ParseBlockID.
+func ParseBlockID(s string) (BlockID, error) { return DefaultBlocks.ParseBlockID(s) }
+
+// ParseBlock parses a block using DefaultBlocks. See BlockRegistry.ParseBlock.
+// The returned block only has ID and Data set.
+func ParseBlock(s string) (Block, error) {
+	id, data, err := DefaultBlocks.ParseBlock(s)
+	if err != nil {
+		return Block{}, err
+	}
+	return Block{ID: id, Data: data}, nil
+}
+
+// Names from: http://minecraft.gamepedia.com/Data_values
+
+var vanillaBlockNames = map[BlockID]string{
+	BlkAir:                        "minecraft:air",
+	BlkStone:                      "minecraft:stone",
+	BlkGrassBlock:                 "minecraft:grass",
+	BlkDirt:                       "minecraft:dirt",
+	BlkCobblestone:                "minecraft:cobblestone",
+	BlkWoodPlanks:                 "minecraft:planks",
+	BlkSaplings:                   "minecraft:sapling",
+	BlkBedrock:                    "minecraft:bedrock",
+	BlkWater:                      "minecraft:flowing_water",
+	BlkStationaryWater:            "minecraft:water",
+	BlkLava:                       "minecraft:flowing_lava",
+	BlkStationaryLava:             "minecraft:lava",
+	BlkSand:                       "minecraft:sand",
+	BlkGravel:                     "minecraft:gravel",
+	BlkGoldOre:                    "minecraft:gold_ore",
+	BlkIronOre:                    "minecraft:iron_ore",
+	BlkCoalOre:                    "minecraft:coal_ore",
+	BlkWood:                       "minecraft:log",
+	BlkLeaves:                     "minecraft:leaves",
+	BlkSponge:                     "minecraft:sponge",
+	BlkGlass:                      "minecraft:glass",
+	BlkLapisLazuliOre:             "minecraft:lapis_ore",
+	BlkLapisLazuliBlock:           "minecraft:lapis_block",
+	BlkDispenser:                  "minecraft:dispenser",
+	BlkSandstone:                  "minecraft:sandstone",
+	BlkNoteBlock:                  "minecraft:noteblock",
+	BlkBed:                        "minecraft:bed",
+	BlkPoweredRail:                "minecraft:golden_rail",
+	BlkDetectorRail:               "minecraft:detector_rail",
+	BlkStickyPiston:               "minecraft:sticky_piston",
+	BlkCobweb:                     "minecraft:web",
+	BlkGrass:                      "minecraft:tallgrass",
+	BlkDeadBush:                   "minecraft:deadbush",
+	BlkPiston:                     "minecraft:piston",
+	BlkPistonExtension:            "minecraft:piston_head",
+	BlkWool:                       "minecraft:wool",
+	BlkBlockMovedByPiston:         "minecraft:piston_extension",
+	BlkDandelion:                  "minecraft:yellow_flower",
+	BlkFlower:                     "minecraft:red_flower",
+	BlkBrownMushroom:              "minecraft:brown_mushroom",
+	BlkRedMushroom:                "minecraft:red_mushroom",
+	BlkBlockOfGold:                "minecraft:gold_block",
+	BlkBlockOfIron:                "minecraft:iron_block",
+	BlkDoubleSlabs:                "minecraft:double_stone_slab",
+	BlkSlabs:                      "minecraft:stone_slab",
+	BlkBricks:                     "minecraft:brick_block",
+	BlkTNT:                        "minecraft:tnt",
+	BlkBookshelf:                  "minecraft:bookshelf",
+	BlkMossStone:                  "minecraft:mossy_cobblestone",
+	BlkObsidian:                   "minecraft:obsidian",
+	BlkTorch:                      "minecraft:torch",
+	BlkFire:                       "minecraft:fire",
+	BlkMonsterSpawner:             "minecraft:mob_spawner",
+	BlkOakWoodStairs:              "minecraft:oak_stairs",
+	BlkChest:                      "minecraft:chest",
+	BlkRedstoneWire:               "minecraft:redstone_wire",
+	BlkDiamondOre:                 "minecraft:diamond_ore",
+	BlkBlockOfDiamond:             "minecraft:diamond_block",
+	BlkCraftingTable:              "minecraft:crafting_table",
+	BlkWheat:                      "minecraft:wheat",
+	BlkFarmland:                   "minecraft:farmland",
+	BlkFurnace:                    "minecraft:furnace",
+	BlkBurningFurnace:             "minecraft:lit_furnace",
+	BlkSignPost:                   "minecraft:standing_sign",
+	BlkWoodenDoor:                 "minecraft:wooden_door",
+	BlkLadders:                    "minecraft:ladder",
+	BlkRail:                       "minecraft:rail",
+	BlkCobblestoneStairs:          "minecraft:stone_stairs",
+	BlkWallSign:                   "minecraft:wall_sign",
+	BlkLever:                      "minecraft:lever",
+	BlkStonePressurePlate:         "minecraft:stone_pressure_plate",
+	BlkIronDoor:                   "minecraft:iron_door",
+	BlkWoodenPressurePlate:        "minecraft:wooden_pressure_plate",
+	BlkRedstoneOre:                "minecraft:redstone_ore",
+	BlkGlowingRedstoneOre:         "minecraft:lit_redstone_ore",
+	BlkRedstoneTorchInactive:      "minecraft:unlit_redstone_torch",
+	BlkRedstoneTorchActive:        "minecraft:redstone_torch",
+	BlkStoneButton:                "minecraft:stone_button",
+	BlkSnow:                       "minecraft:snow_layer",
+	BlkIce:                        "minecraft:ice",
+	BlkSnowBlock:                  "minecraft:snow",
+	BlkCactus:                     "minecraft:cactus",
+	BlkClay:                       "minecraft:clay",
+	BlkSugarCane:                  "minecraft:reeds",
+	BlkJukebox:                    "minecraft:jukebox",
+	BlkFence:                      "minecraft:fence",
+	BlkPumpkin:                    "minecraft:pumpkin",
+	BlkNetherrack:                 "minecraft:netherrack",
+	BlkSoulSand:                   "minecraft:soul_sand",
+	BlkGlowstone:                  "minecraft:glowstone",
+	BlkNetherPortal:               "minecraft:portal",
+	BlkJackOLantern:               "minecraft:lit_pumpkin",
+	BlkCakeBlock:                  "minecraft:cake",
+	BlkRedstoneRepeaterInactive:   "minecraft:unpowered_repeater",
+	BlkRedstoneRepeaterActive:     "minecraft:powered_repeater",
+	BlkStainedGlass:               "minecraft:stained_glass",
+	BlkTrapdoor:                   "minecraft:trapdoor",
+	BlkMonsterEgg:                 "minecraft:monster_egg",
+	BlkStoneBricks:                "minecraft:stonebrick",
+	BlkHugeBrownMushroom:          "minecraft:brown_mushroom_block",
+	BlkHugeRedMushroom:            "minecraft:red_mushroom_block",
+	BlkIronBars:                   "minecraft:iron_bars",
+	BlkGlassPane:                  "minecraft:glass_pane",
+	BlkMelon:                      "minecraft:melon_block",
+	BlkPumpkinStem:                "minecraft:pumpkin_stem",
+	BlkMelonStem:                  "minecraft:melon_stem",
+	BlkVines:                      "minecraft:vine",
+	BlkFenceGate:                  "minecraft:fence_gate",
+	BlkBrickStairs:                "minecraft:brick_stairs",
+	BlkStoneBrickStairs:           "minecraft:stone_brick_stairs",
+	BlkMycelium:                   "minecraft:mycelium",
+	BlkLilyPad:                    "minecraft:waterlily",
+	BlkNetherBrick:                "minecraft:nether_brick",
+	BlkNetherBrickFence:           "minecraft:nether_brick_fence",
+	BlkNetherBrickStairs:          "minecraft:nether_brick_stairs",
+	BlkNetherWart:                 "minecraft:nether_wart",
+	BlkEnchantmentTable:           "minecraft:enchanting_table",
+	BlkBrewingStand:               "minecraft:brewing_stand",
+	BlkCauldron:                   "minecraft:cauldron",
+	BlkEndPortal:                  "minecraft:end_portal",
+	BlkEndPortalBlock:             "minecraft:end_portal_frame",
+	BlkEndStone:                   "minecraft:end_stone",
+	BlkDragonEgg:                  "minecraft:dragon_egg",
+	BlkRedstoneLampInactive:       "minecraft:redstone_lamp",
+	BlkRedstoneLampActive:         "minecraft:lit_redstone_lamp",
+	BlkWoodenDoubleSlab:           "minecraft:double_wooden_slab",
+	BlkWoodenSlab:                 "minecraft:wooden_slab",
+	BlkCocoa:                      "minecraft:cocoa",
+	BlkSandstoneStairs:            "minecraft:sandstone_stairs",
+	BlkEmeraldOre:                 "minecraft:emerald_ore",
+	BlkEnderChest:                 "minecraft:ender_chest",
+	BlkTripwireHook:               "minecraft:tripwire_hook",
+	BlkTripwire:                   "minecraft:tripwire",
+	BlkBlockOfEmerald:             "minecraft:emerald_block",
+	BlkSpruceWoodStairs:           "minecraft:spruce_stairs",
+	BlkBirchWoodStairs:            "minecraft:birch_stairs",
+	BlkJungleWoodStairs:           "minecraft:jungle_stairs",
+	BlkCommandBlock:               "minecraft:command_block",
+	BlkBeacon:                     "minecraft:beacon",
+	BlkCobblestoneWall:            "minecraft:cobblestone_wall",
+	BlkFlowerPot:                  "minecraft:flower_pot",
+	BlkCarrots:                    "minecraft:carrots",
+	BlkPotatoes:                   "minecraft:potatoes",
+	BlkWoodenButton:               "minecraft:wooden_button",
+	BlkMobHead:                    "minecraft:skull",
+	BlkAnvil:                      "minecraft:anvil",
+	BlkTrappedChest:               "minecraft:trapped_chest",
+	BlkWeightedPressurePlateLight: "minecraft:light_weighted_pressure_plate",
+	BlkWeightedPressurePlateHeavy: "minecraft:heavy_weighted_pressure_plate",
+	BlkRedstoneComparatorInactive: "minecraft:unpowered_comparator",
+	BlkRedstoneComparatorActive:   "minecraft:powered_comparator",
+	BlkDaylightSensor:             "minecraft:daylight_detector",
+	BlkBlockOfRedstone:            "minecraft:redstone_block",
+	BlkNetherQuartzOre:            "minecraft:quartz_ore",
+	BlkHopper:                     "minecraft:hopper",
+	BlkBlockOfQuartz:              "minecraft:quartz_block",
+	BlkQuartzStairs:               "minecraft:quartz_stairs",
+	BlkActivatorRail:              "minecraft:activator_rail",
+	BlkDropper:                    "minecraft:dropper",
+	BlkStainedClay:                "minecraft:stained_hardened_clay",
+	BlkStainedGlassPane:           "minecraft:stained_glass_pane",
+	BlkLeaves2:                    "minecraft:leaves2",
+	BlkWood2:                      "minecraft:log2",
+	BlkAcaciaWoodStairs:           "minecraft:acacia_stairs",
+	BlkDarkOakWoodStairs:          "minecraft:dark_oak_stairs",
+	BlkSlimeBlock:                 "minecraft:slime",
+	BlkBarrier:                    "minecraft:barrier",
+	BlkIronTrapdoor:               "minecraft:iron_trapdoor",
+	BlkPrismarine:                 "minecraft:prismarine",
+	BlkSeaLantern:                 "minecraft:sea_lantern",
+	BlkHayBlock:                   "minecraft:hay_block",
+	BlkCarpet:                     "minecraft:carpet",
+	BlkHardenedClay:               "minecraft:hardened_clay",
+	BlkBlockOfCoal:                "minecraft:coal_block",
+	BlkPackedIce:                  "minecraft:packed_ice",
+	BlkLargeFlower:                "minecraft:double_plant",
+	BlkStandingBanner:             "minecraft:standing_banner",
+	BlkWallBanner:                 "minecraft:wall_banner",
+	BlkInvertedDaylightSensor:     "minecraft:daylight_detector_inverted",
+	BlkRedSandstone:               "minecraft:red_sandstone",
+	BlkRedSandstoneStairs:         "minecraft:red_sandstone_stairs",
+	BlkDoubleRedSandstoneSlab:     "minecraft:double_stone_slab2",
+	BlkRedSandstoneSlab:           "minecraft:stone_slab2",
+	BlkSpruceFenceGate:            "minecraft:spruce_fence_gate",
+	BlkBirchFenceGate:             "minecraft:birch_fence_gate",
+	BlkJungleFenceGate:            "minecraft:jungle_fence_gate",
+	BlkDarkOakFenceGate:           "minecraft:dark_oak_fence_gate",
+	BlkAcaciaFenceGate:            "minecraft:acacia_fence_gate",
+	BlkSpruceFence:                "minecraft:spruce_fence",
+	BlkBirchFence:                 "minecraft:birch_fence",
+	BlkJungleFence:                "minecraft:jungle_fence",
+	BlkDarkOakFence:               "minecraft:dark_oak_fence",
+	BlkAcaciaFence:                "minecraft:acacia_fence",
+	BlkSpruceDoor:                 "minecraft:spruce_door",
+	BlkBirchDoor:                  "minecraft:birch_door",
+	BlkJungleDoor:                 "minecraft:jungle_door",
+	BlkAcaciaDoor:                 "minecraft:acacia_door",
+	BlkDarkOakDoor:                "minecraft:dark_oak_door",
+	BlkEndRod:                     "minecraft:end_rod",
+	BlkChorusPlant:                "minecraft:chorus_plant",
+	BlkChorusFlower:               "minecraft:chorus_flower",
+	BlkPurpurBlock:                "minecraft:purpur_block",
+	BlkPurpurPillar:               "minecraft:purpur_pillar",
+	BlkPurpurStairs:               "minecraft:purpur_stairs",
+	BlkPurpurDoubleSlab:           "minecraft:purpur_double_slab",
+	BlkPurpurSlab:                 "minecraft:purpur_slab",
+	BlkEndStoneBricks:             "minecraft:end_bricks",
+	BlkBeetroots:                  "minecraft:beetroots",
+	BlkGrassPath:                  "minecraft:grass_path",
+	BlkEndGateway:                 "minecraft:end_gateway",
+	BlkRepeatingCommandBlock:      "minecraft:repeating_command_block",
+	BlkChainCommandBlock:          "minecraft:chain_command_block",
+	BlkFrostedIce:                 "minecraft:frosted_ice",
+	BlkMagmaBlock:                 "minecraft:magma",
+	BlkNetherWartBlock:            "minecraft:nether_wart_block",
+	BlkRedNetherBrick:             "minecraft:red_nether_brick",
+	BlkBoneBlock:                  "minecraft:bone_block",
+	BlkStructureVoid:              "minecraft:structure_void",
+	BlkObserver:                   "minecraft:observer",
+	BlkWhiteShulkerBox:            "minecraft:white_shulker_box",
+	BlkOrangeShulkerBox:           "minecraft:orange_shulker_box",
+	BlkMagentaShulkerBox:          "minecraft:magenta_shulker_box",
+	BlkLightBlueShulkerBox:        "minecraft:light_blue_shulker_box",
+	BlkYellowShulkerBox:           "minecraft:yellow_shulker_box",
+	BlkLimeShulkerBox:             "minecraft:lime_shulker_box",
+	BlkPinkShulkerBox:             "minecraft:pink_shulker_box",
+	BlkGrayShulkerBox:             "minecraft:gray_shulker_box",
+	BlkLightGrayShulkerBox:        "minecraft:silver_shulker_box",
+	BlkCyanShulkerBox:             "minecraft:cyan_shulker_box",
+	BlkPurpleShulkerBox:           "minecraft:purple_shulker_box",
+	BlkBlueShulkerBox:             "minecraft:blue_shulker_box",
+	BlkBrownShulkerBox:            "minecraft:brown_shulker_box",
+	BlkGreenShulkerBox:            "minecraft:green_shulker_box",
+	BlkRedShulkerBox:              "minecraft:red_shulker_box",
+	BlkBlackShulkerBox:            "minecraft:black_shulker_box",
+	BlkWhiteGlazedTerracotta:      "minecraft:white_glazed_terracotta",
+	BlkOrangeGlazedTerracotta:     "minecraft:orange_glazed_terracotta",
+	BlkMagentaGlazedTerracotta:    "minecraft:magenta_glazed_terracotta",
+	BlkLightBlueGlazedTerracotta:  "minecraft:light_blue_glazed_terracotta",
+	BlkYellowGlazedTerracotta:     "minecraft:yellow_glazed_terracotta",
+	BlkLimeGlazedTerracotta:       "minecraft:lime_glazed_terracotta",
+	BlkPinkGlazedTerracotta:       "minecraft:pink_glazed_terracotta",
+	BlkGrayGlazedTerracotta:       "minecraft:gray_glazed_terracotta",
+	BlkLightGrayGlazedTerracotta:  "minecraft:silver_glazed_terracotta",
+	BlkCyanGlazedTerracotta:       "minecraft:cyan_glazed_terracotta",
+	BlkPurpleGlazedTerracotta:     "minecraft:purple_glazed_terracotta",
+	BlkBlueGlazedTerracotta:       "minecraft:blue_glazed_terracotta",
+	BlkBrownGlazedTerracotta:      "minecraft:brown_glazed_terracotta",
+	BlkGreenGlazedTerracotta:      "minecraft:green_glazed_terracotta",
+	BlkRedGlazedTerracotta:        "minecraft:red_glazed_terracotta",
+	BlkBlackGlazedTerracotta:      "minecraft:black_glazed_terracotta",
+	BlkConcrete:                   "minecraft:concrete",
+	BlkConcretePowder:             "minecraft:concrete_powder",
+	BlkStructureBlock:             "minecraft:structure_block",
+}
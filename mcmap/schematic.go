@@ -0,0 +1,410 @@
+package mcmap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"io"
+	"sort"
+)
+
+// Schematic is a cuboid of blocks with their tile entities and the entities inside, that can be exported from a region
+// and pasted into a region. It can be read from and written to MCEdit .schematic, Sponge .schem and structure block .nbt files.
+type Schematic struct {
+	Width, Height, Length int // Size in X, Y and Z direction
+
+	// Entities in the schematic. Their positions are relative to the origin of the schematic.
+	Entities []*Entity
+
+	// Data version of the blocks, tile entities and entities (see Chunk.DataVersion).
+	DataVersion int32
+
+	blocks      []Block         // Ordered YZX
+	extra       nbt.TagCompound // Root tags not interpreted by us
+	extraFormat string          // Format of the file, the extra tags were read from
+}
+
+// Formats of schematic files, used to decide which extra tags can be written.
+const (
+	formatMCEdit    = "mcedit"
+	formatSponge    = "sponge"
+	formatStructure = "structure"
+)
+
+// NewSchematic creates an empty schematic (filled with air) of the given size.
+func NewSchematic(width, height, length int) *Schematic {
+	return &Schematic{
+		Width:  width,
+		Height: height,
+		Length: length,
+		blocks: make([]Block, width*height*length),
+	}
+}
+
+// extraTags returns a copy of the extra tags, unless they were read from a file of another format.
+func (s *Schematic) extraTags(format string) nbt.TagCompound {
+	if (s.extraFormat != "" && s.extraFormat != format) || s.extra == nil {
+		return make(nbt.TagCompound)
+	}
+	return copyCompound(s.extra)
+}
+
+func (s *Schematic) blockOffset(x, y, z int) int {
+	if x < 0 || y < 0 || z < 0 || x >= s.Width || y >= s.Height || z >= s.Length {
+		return -1
+	}
+	return (y*s.Length+z)*s.Width + x
+}
+
+// Block returns the block at x, y, z (relative to the origin of the schematic) or nil, if the position is outside.
+// The coordinates of the tile entity will be fixed, when the schematic is written or pasted.
+func (s *Schematic) Block(x, y, z int) *Block {
+	off := s.blockOffset(x, y, z)
+	if off < 0 {
+		return nil
+	}
+	return &s.blocks[off]
+}
+
+// ExtraTags returns the root tags of the schematic file, that are not interpreted by gomcmap.
+// They are written unchanged, unless the schematic is written in another format than it was read from.
+func (s *Schematic) ExtraTags() nbt.TagCompound {
+	if s.extra == nil {
+		s.extra = make(nbt.TagCompound)
+	}
+	return s.extra
+}
+
+// Root tags of MCEdit schematics interpreted by ReadMCEditSchematic.
+var mcEditTags = map[string]bool{
+	"Width":        true,
+	"Height":       true,
+	"Length":       true,
+	"Blocks":       true,
+	"AddBlocks":    true,
+	"Data":         true,
+	"Entities":     true,
+	"TileEntities": true,
+}
+
+// ReadMCEditSchematic reads a schematic in the MCEdit format (gzip compressed NBT) with numeric block IDs.
+func ReadMCEditSchematic(r io.Reader) (*Schematic, error) {
+	tag, _, err := nbt.ReadGzipdNamedTag(r)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Type != nbt.TAG_Compound {
+		return nil, errors.New("Root tag is not a TAG_Compound")
+	}
+	root := tag.Payload.(nbt.TagCompound)
+
+	var size [3]int16
+	for i, name := range []string{"Width", "Height", "Length"} {
+		if size[i], err = root.GetShort(name); err != nil {
+			return nil, fmt.Errorf("Could not read %s tag: %s", name, err)
+		}
+	}
+	s := NewSchematic(int(uint16(size[0])), int(uint16(size[1])), int(uint16(size[2])))
+	s.extra, s.extraFormat = unknownTags(root, mcEditTags), formatMCEdit
+
+	blocks, err := root.GetByteArray("Blocks")
+	if err != nil {
+		return nil, fmt.Errorf("Could not read Blocks tag: %s", err)
+	}
+	data, err := root.GetByteArray("Data")
+	if err != nil {
+		return nil, fmt.Errorf("Could not read Data tag: %s", err)
+	}
+	add, err := root.GetByteArray("AddBlocks")
+	if optional(err) != nil {
+		return nil, fmt.Errorf("Could not read AddBlocks tag: %s", err)
+	}
+	if len(blocks) != len(s.blocks) || len(data) != len(s.blocks) || (add != nil && len(add) != (len(s.blocks)+1)/2) {
+		return nil, errors.New("Size of Blocks, Data or AddBlocks does not match the dimensions")
+	}
+
+	for i := range s.blocks {
+		id := BlockID(blocks[i])
+		if add != nil {
+			// Unlike the nibble arrays of chunks, the first block is stored in the high nibble.
+			id |= BlockID((add[i/2]>>uint(4*(1-i%2)))&0xf) << 8
+		}
+		s.blocks[i].ID = id
+		s.blocks[i].Data = data[i] & 0xf
+	}
+
+	if err := s.readTileEntities(root, "TileEntities"); err != nil {
+		return nil, err
+	}
+	if err := s.readEntities(root, "Entities"); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// readTileEntities reads a list of tile entities with coordinates relative to the schematic.
+func (s *Schematic) readTileEntities(root nbt.TagCompound, name string) error {
+	tileEnts, err := root.GetList(name)
+	if err != nil || tileEnts.Type != nbt.TAG_Compound {
+		return optional(err)
+	}
+
+	for _, elem := range tileEnts.Elems {
+		te := elem.(nbt.TagCompound)
+		x, y, z, err := extractCoord(te)
+		if err != nil {
+			return fmt.Errorf("Could not read %s tag: %s", name, err)
+		}
+		if blk := s.Block(x, y, z); blk != nil {
+			blk.TileEntity = te
+		}
+	}
+	return nil
+}
+
+// readEntities reads a list of entities with positions relative to the schematic.
+func (s *Schematic) readEntities(root nbt.TagCompound, name string) error {
+	ents, err := root.GetList(name)
+	if err != nil || ents.Type != nbt.TAG_Compound {
+		return optional(err)
+	}
+
+	for _, elem := range ents.Elems {
+		ent, err := EntityFromNBT(elem.(nbt.TagCompound))
+		if err != nil {
+			return fmt.Errorf("Could not read %s tag: %s", name, err)
+		}
+		s.Entities = append(s.Entities, ent)
+	}
+	return nil
+}
+
+// WriteMCEdit writes the schematic in the MCEdit format. Blocks without a numeric ID (only possible for blocks from
+// Minecraft 1.13+) are written as air.
+func (s *Schematic) WriteMCEdit(w io.Writer) error {
+	root := s.extraTags(formatMCEdit)
+	if _, ok := root["Materials"]; !ok {
+		root["Materials"] = nbt.Tag{nbt.TAG_String, "Alpha"}
+	}
+
+	root["Width"] = nbt.NewShortTag(int16(s.Width))
+	root["Height"] = nbt.NewShortTag(int16(s.Height))
+	root["Length"] = nbt.NewShortTag(int16(s.Length))
+
+	blocks := make([]byte, len(s.blocks))
+	data := make([]byte, len(s.blocks))
+	add := make([]byte, (len(s.blocks)+1)/2)
+	hasAdd := false
+	for i, blk := range s.blocks {
+		if blk.ID > maxBlockID {
+			continue
+		}
+		blocks[i] = byte(blk.ID)
+		data[i] = blk.Data & 0xf
+		if high := byte(blk.ID >> 8); high != 0 {
+			add[i/2] |= high << uint(4*(1-i%2))
+			hasAdd = true
+		}
+	}
+	root["Blocks"] = nbt.NewByteArrayTag(blocks)
+	root["Data"] = nbt.NewByteArrayTag(data)
+	if hasAdd {
+		root["AddBlocks"] = nbt.NewByteArrayTag(add)
+	} else {
+		delete(root, "AddBlocks")
+	}
+
+	root["TileEntities"] = nbt.NewListTag(nbt.TAG_Compound, s.tileEntities())
+	root["Entities"] = nbt.NewListTag(nbt.TAG_Compound, s.entities())
+
+	return nbt.WriteGzipdNamedTag(w, "Schematic", nbt.Tag{nbt.TAG_Compound, root})
+}
+
+// tileEntities returns copies of the tile entities with coordinates relative to the schematic.
+func (s *Schematic) tileEntities() []nbt.TagCompound {
+	var tileEnts []nbt.TagCompound
+	for off, blk := range s.blocks {
+		if len(blk.TileEntity) == 0 {
+			continue
+		}
+
+		x, y, z := off%s.Width, off/(s.Width*s.Length), (off/s.Width)%s.Length
+		te := copyCompound(blk.TileEntity)
+		te["x"] = nbt.NewIntTag(int32(x))
+		te["y"] = nbt.NewIntTag(int32(y))
+		te["z"] = nbt.NewIntTag(int32(z))
+		tileEnts = append(tileEnts, te)
+	}
+	return tileEnts
+}
+
+func (s *Schematic) entities() []nbt.TagCompound {
+	ents := make([]nbt.TagCompound, len(s.Entities))
+	for i, ent := range s.Entities {
+		ents[i] = ent.toNBT(s.DataVersion)
+	}
+	return ents
+}
+
+// ExportSchematic copies the cuboid with the lowest corner x, y, z (global coordinates) and the given size
+// from the region into a new schematic. Blocks in unavailable chunks are air.
+func (reg *Region) ExportSchematic(x, y, z, width, height, length int) (*Schematic, error) {
+	s := NewSchematic(width, height, length)
+	s.DataVersion = reg.dataVersion
+	x1, y1, z1 := x+width-1, y+height-1, z+length-1
+
+	err := reg.forChunksIn(x, z, x1, z1, func(c *Chunk) error {
+		s.DataVersion = c.dataVersion
+
+		minX, minY, minZ, maxX, maxY, maxZ, ok := c.chunkArea(x, y, z, x1, y1, z1)
+		if !ok {
+			return nil
+		}
+		bx, bz := ChunkToBlock(int(c.x), int(c.z), 0, 0)
+		for cy := minY; cy <= maxY; cy++ {
+			for cz := minZ; cz <= maxZ; cz++ {
+				for cx := minX; cx <= maxX; cx++ {
+					*s.Block(bx+cx-x, cy-y, bz+cz-z) = copyBlock(*c.Block(cx, cy, cz))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ents, err := reg.findEntities(Box{float64(x), float64(y), float64(z), float64(x1 + 1), float64(y1 + 1), float64(z1 + 1)}, func(e *Entity) bool {
+		return e.Pos[0] < float64(x1+1) && e.Pos[1] < float64(y1+1) && e.Pos[2] < float64(z1+1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ent := range ents {
+		s.Entities = append(s.Entities, ent.translated(-x, -y, -z))
+	}
+
+	return s, nil
+}
+
+// PasteSchematic copies the blocks, tile entities and entities of the schematic into the region, with the origin of the
+// schematic at x, y, z (global coordinates). Blocks in unavailable chunks or outside of the vertical range are skipped.
+// The pasted entities get new UUIDs. The light of the modified chunks is recalculated.
+//
+// Blocks without a numeric ID (see BlockState.Legacy) are mapped through the block registry of the region (see Region.Blocks),
+// when they are pasted into chunks of a version before Minecraft 1.13. Blocks that can not be mapped are replaced by air,
+// their names are returned in unmapped.
+func (reg *Region) PasteSchematic(s *Schematic, x, y, z int) (unmapped []string, err error) {
+	return reg.pasteBlocks(s, x, y, z, func(sx, sy, sz int, blk *Block) (Block, bool) {
+		return *blk, true
+	})
+}
+
+// pasteBlocks pastes the schematic like PasteSchematic. The block of the schematic at sx, sy, sz is mapped to the pasted block
+// by f, which can also skip the block.
+func (reg *Region) pasteBlocks(s *Schematic, x, y, z int, f func(sx, sy, sz int, blk *Block) (Block, bool)) (unmapped []string, err error) {
+	x1, y1, z1 := x+s.Width-1, y+s.Height-1, z+s.Length-1
+
+	blocks := reg.Blocks()
+	unmappedNames := make(map[string]bool)
+
+	var modified []XZPos
+	err = reg.forChunksIn(x, z, x1, z1, func(c *Chunk) error {
+		minX, minY, minZ, maxX, maxY, maxZ, ok := c.chunkArea(x, y, z, x1, y1, z1)
+		if !ok {
+			return nil
+		}
+		bx, bz := ChunkToBlock(int(c.x), int(c.z), 0, 0)
+		for cy := minY; cy <= maxY; cy++ {
+			for cz := minZ; cz <= maxZ; cz++ {
+				for cx := minX; cx <= maxX; cx++ {
+					sx, sy, sz := bx+cx-x, cy-y, bz+cz-z
+					blk, ok := f(sx, sy, sz, s.Block(sx, sy, sz))
+					if !ok {
+						continue
+					}
+					blk = copyBlock(blk)
+
+					if c.dataVersion < dataVersionFlattening && !legacyBlock(&blk, blocks) {
+						unmappedNames[blk.BlockState().Name] = true
+						blk = Block{}
+					}
+					if blk.TileEntity != nil {
+						convertTileEntityID(blk.TileEntity, blk.ID, s.DataVersion, c.dataVersion)
+					}
+
+					*c.Block(cx, cy, cz) = blk
+				}
+			}
+		}
+		c.MarkModified()
+		modified = append(modified, XZPos{int(c.x), int(c.z)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ent := range s.Entities {
+		ent = ent.translated(x, y, z)
+		ent.UUID = NewUUID()
+		if err := reg.addEntity(ent, s.DataVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	for name := range unmappedNames {
+		unmapped = append(unmapped, name)
+	}
+	sort.Strings(unmapped)
+
+	return unmapped, reg.RecalcLight(modified)
+}
+
+// legacyBlock makes sure, the block has a numeric ID that can be stored in chunks before Minecraft 1.13.
+// Blocks with a dynamically allocated ID are looked up by name in blocks (e.g. blocks of mods). Returns false, if that fails.
+func legacyBlock(blk *Block, blocks *BlockRegistry) bool {
+	if blk.ID <= maxBlockID {
+		return true
+	}
+
+	id, ok := blocks.ID(blk.BlockState().Name)
+	if !ok || id > maxBlockID {
+		return false
+	}
+	blk.ID, blk.Data, blk.State = id, 0, nil
+	return true
+}
+
+// convertTileEntityID converts the ID of a tile entity of the block with the ID blkID from the format of the data version from
+// to the format of the data version to.
+func convertTileEntityID(te nbt.TagCompound, blkID BlockID, from, to int32) {
+	id, err := te.GetString("id")
+	if err != nil {
+		return
+	}
+
+	switch {
+	case from < dataVersionFlattening && to >= dataVersionFlattening:
+		te["id"] = nbt.Tag{nbt.TAG_String, upgradeTileEntityID(id, blkID)}
+	case from < dataVersionNamespacedIDs && to >= dataVersionNamespacedIDs:
+		te["id"] = nbt.Tag{nbt.TAG_String, upgradeTileEntityID(id, BlkAir)} // Trapped chests have their own ID since 1.13.
+	case from >= dataVersionNamespacedIDs && to < dataVersionNamespacedIDs:
+		te["id"] = nbt.Tag{nbt.TAG_String, tileEntityID(id, to)}
+	}
+}
+
+// convertEntityID converts the ID of an entity from the format of the data version from to the format of the data version to.
+// The tags distinguishing the entity types split in Minecraft 1.11 may be removed from the NBT data, so ent should be a copy.
+func convertEntityID(ent *Entity, from, to int32) {
+	switch {
+	case ent.ID == "":
+	case from < dataVersionFlattening && to >= dataVersionFlattening:
+		ent.ID = upgradeEntityID(ent.ID, ent.NBT)
+	case from < dataVersionNamespacedIDs && to >= dataVersionNamespacedIDs:
+		ent.ID = namespacedEntityID(ent.ID, ent.NBT)
+	case from > to:
+		ent.ID = entityID(upgradeEntityID(ent.ID, nil), to)
+	}
+}
@@ -0,0 +1,346 @@
+package mcmap
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	UnmappableBlock = errors.New("Block can not be stored in chunks before Minecraft 1.13")
+)
+
+// Selection is a set of blocks in global coordinates, used by Region.Fill, Region.Replace, Region.Walls and Region.Outline.
+type Selection interface {
+	// Contains checks, if the block at x, y, z is selected.
+	Contains(x, y, z int) bool
+
+	// Bounds returns a cuboid containing all selected blocks. Only the chunks intersecting the bounds are visited.
+	Bounds() Cuboid
+}
+
+// Cuboid selects all blocks from MinX, MinY, MinZ to MaxX, MaxY, MaxZ (inclusive).
+// The cuboid is empty, if a minimum is greater than the maximum.
+type Cuboid struct {
+	MinX, MinY, MinZ int
+	MaxX, MaxY, MaxZ int
+}
+
+// NewCuboid creates a cuboid with the corners x0, y0, z0 and x1, y1, z1 (in any order).
+func NewCuboid(x0, y0, z0, x1, y1, z1 int) Cuboid {
+	return Cuboid{minInt(x0, x1), minInt(y0, y1), minInt(z0, z1), maxInt(x0, x1), maxInt(y0, y1), maxInt(z0, z1)}
+}
+
+func (c Cuboid) Contains(x, y, z int) bool {
+	return x >= c.MinX && x <= c.MaxX && y >= c.MinY && y <= c.MaxY && z >= c.MinZ && z <= c.MaxZ
+}
+
+func (c Cuboid) Bounds() Cuboid { return c }
+
+// Sphere selects all blocks whose distance to the center X, Y, Z is at most Radius.
+type Sphere struct {
+	X, Y, Z int
+	Radius  float64
+}
+
+func (s Sphere) Contains(x, y, z int) bool {
+	dx, dy, dz := float64(x-s.X), float64(y-s.Y), float64(z-s.Z)
+	return dx*dx+dy*dy+dz*dz <= s.Radius*s.Radius
+}
+
+func (s Sphere) Bounds() Cuboid {
+	r := int(math.Floor(s.Radius))
+	return Cuboid{s.X - r, s.Y - r, s.Z - r, s.X + r, s.Y + r, s.Z + r}
+}
+
+// Cylinder selects all blocks from MinY to MaxY (inclusive), whose horizontal distance to the axis at X, Z is at most Radius.
+type Cylinder struct {
+	X, Z       int
+	MinY, MaxY int
+	Radius     float64
+}
+
+func (c Cylinder) Contains(x, y, z int) bool {
+	dx, dz := float64(x-c.X), float64(z-c.Z)
+	return y >= c.MinY && y <= c.MaxY && dx*dx+dz*dz <= c.Radius*c.Radius
+}
+
+func (c Cylinder) Bounds() Cuboid {
+	r := int(math.Floor(c.Radius))
+	return Cuboid{c.X - r, c.MinY, c.Z - r, c.X + r, c.MaxY, c.Z + r}
+}
+
+// Polygon selects all blocks from MinY to MaxY (inclusive) inside of the polygon with the corners Points (block coordinates).
+// Blocks on the edges of the polygon are selected too.
+type Polygon struct {
+	Points     []XZPos
+	MinY, MaxY int
+}
+
+func (p Polygon) Contains(x, y, z int) bool {
+	if y < p.MinY || y > p.MaxY || len(p.Points) == 0 {
+		return false
+	}
+
+	inside := false
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+
+		// On the edge from a to b?
+		if (b.X-a.X)*(z-a.Z) == (b.Z-a.Z)*(x-a.X) && x >= minInt(a.X, b.X) && x <= maxInt(a.X, b.X) && z >= minInt(a.Z, b.Z) && z <= maxInt(a.Z, b.Z) {
+			return true
+		}
+
+		// Even-odd rule
+		if (a.Z > z) != (b.Z > z) {
+			if float64(x) < float64(b.X-a.X)*float64(z-a.Z)/float64(b.Z-a.Z)+float64(a.X) {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+func (p Polygon) Bounds() Cuboid {
+	if len(p.Points) == 0 {
+		return emptyCuboid
+	}
+
+	bounds := Cuboid{p.Points[0].X, p.MinY, p.Points[0].Z, p.Points[0].X, p.MaxY, p.Points[0].Z}
+	for _, pt := range p.Points[1:] {
+		bounds.MinX, bounds.MaxX = minInt(bounds.MinX, pt.X), maxInt(bounds.MaxX, pt.X)
+		bounds.MinZ, bounds.MaxZ = minInt(bounds.MinZ, pt.Z), maxInt(bounds.MaxZ, pt.Z)
+	}
+	return bounds
+}
+
+var emptyCuboid = Cuboid{0, 0, 0, -1, -1, -1}
+
+// Union selects all blocks selected by at least one of its selections.
+// Region.Fill and friends visit the bounds of the selections separately, so chunks between them are not loaded.
+type Union []Selection
+
+func (u Union) Contains(x, y, z int) bool {
+	for _, sel := range u {
+		if sel.Contains(x, y, z) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u Union) Bounds() Cuboid {
+	bounds := emptyCuboid
+	first := true
+	for _, sel := range u {
+		b := sel.Bounds()
+		if b.MinX > b.MaxX || b.MinY > b.MaxY || b.MinZ > b.MaxZ {
+			continue
+		}
+		if first {
+			bounds, first = b, false
+			continue
+		}
+		bounds = Cuboid{
+			minInt(bounds.MinX, b.MinX), minInt(bounds.MinY, b.MinY), minInt(bounds.MinZ, b.MinZ),
+			maxInt(bounds.MaxX, b.MaxX), maxInt(bounds.MaxY, b.MaxY), maxInt(bounds.MaxZ, b.MaxZ),
+		}
+	}
+	return bounds
+}
+
+// Intersection selects all blocks selected by all of its selections.
+type Intersection []Selection
+
+func (is Intersection) Contains(x, y, z int) bool {
+	for _, sel := range is {
+		if !sel.Contains(x, y, z) {
+			return false
+		}
+	}
+	return len(is) > 0
+}
+
+func (is Intersection) Bounds() Cuboid {
+	if len(is) == 0 {
+		return emptyCuboid
+	}
+
+	bounds := is[0].Bounds()
+	for _, sel := range is[1:] {
+		b := sel.Bounds()
+		bounds = Cuboid{
+			maxInt(bounds.MinX, b.MinX), maxInt(bounds.MinY, b.MinY), maxInt(bounds.MinZ, b.MinZ),
+			minInt(bounds.MaxX, b.MaxX), minInt(bounds.MaxY, b.MaxY), minInt(bounds.MaxZ, b.MaxZ),
+		}
+	}
+	return bounds
+}
+
+// border selects the blocks of a selection, that have an unselected neighbour in one of the directions.
+type border struct {
+	Selection
+	directions []Facing
+}
+
+var facingOffsets = map[Facing][3]int{
+	FacingDown:  {0, -1, 0},
+	FacingUp:    {0, 1, 0},
+	FacingNorth: {0, 0, -1},
+	FacingSouth: {0, 0, 1},
+	FacingWest:  {-1, 0, 0},
+	FacingEast:  {1, 0, 0},
+}
+
+func (b border) Contains(x, y, z int) bool {
+	if !b.Selection.Contains(x, y, z) {
+		return false
+	}
+	for _, f := range b.directions {
+		off := facingOffsets[f]
+		if !b.Selection.Contains(x+off[0], y+off[1], z+off[2]) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectionParts splits a selection into parts, whose bounds are visited separately by applySelection.
+// The members of a union become separate parts, so the chunks between them are not visited. No block is in more than one part.
+func selectionParts(sel Selection) []Selection {
+	switch sel := sel.(type) {
+	case Union:
+		var parts []Selection
+		for i, member := range sel {
+			for _, part := range selectionParts(member) {
+				if i > 0 {
+					part = difference{part, sel[:i]}
+				}
+				parts = append(parts, part)
+			}
+		}
+		return parts
+	case border:
+		parts := selectionParts(sel.Selection)
+		for i, part := range parts {
+			parts[i] = clipped{part, sel}
+		}
+		return parts
+	}
+	return []Selection{sel}
+}
+
+// difference selects the blocks of a selection, that are not selected by exclude.
+type difference struct {
+	Selection
+	exclude Selection
+}
+
+func (d difference) Contains(x, y, z int) bool {
+	return d.Selection.Contains(x, y, z) && !d.exclude.Contains(x, y, z)
+}
+
+// clipped selects the blocks of sel within the selection part.
+type clipped struct {
+	part, sel Selection
+}
+
+func (c clipped) Contains(x, y, z int) bool {
+	return c.part.Contains(x, y, z) && c.sel.Contains(x, y, z)
+}
+
+func (c clipped) Bounds() Cuboid { return c.part.Bounds() }
+
+// applySelection calls f for all selected blocks in the available chunks with blk in the format of the chunk (see legacyBlock).
+// f returns true, if it changed the block. Chunks with changed blocks are marked as modified, so their height maps will be updated.
+//
+// If blk can not be stored in chunks before Minecraft 1.13, these chunks are skipped and UnmappableBlock is returned.
+func (reg *Region) applySelection(sel Selection, blk Block, f func(dst *Block, blk Block) bool) (changed int, err error) {
+	legacy := blk
+	mappable := legacyBlock(&legacy, reg.Blocks())
+	skipped := false
+
+	for _, part := range selectionParts(sel) {
+		b := part.Bounds()
+		err = reg.forChunksIn(b.MinX, b.MinZ, b.MaxX, b.MaxZ, func(c *Chunk) error {
+			minX, minY, minZ, maxX, maxY, maxZ, ok := c.chunkArea(b.MinX, b.MinY, b.MinZ, b.MaxX, b.MaxY, b.MaxZ)
+			if !ok {
+				return nil
+			}
+
+			src := blk
+			if c.dataVersion < dataVersionFlattening {
+				if !mappable {
+					skipped = true
+					return nil
+				}
+				src = legacy
+			}
+
+			bx, bz := ChunkToBlock(int(c.x), int(c.z), 0, 0)
+			modified := false
+			for y := minY; y <= maxY; y++ {
+				for z := minZ; z <= maxZ; z++ {
+					for x := minX; x <= maxX; x++ {
+						if part.Contains(bx+x, y, bz+z) && f(c.Block(x, y, z), src) {
+							changed++
+							modified = true
+						}
+					}
+				}
+			}
+
+			if modified {
+				c.MarkModified()
+			}
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	if skipped {
+		err = UnmappableBlock
+	}
+	return
+}
+
+// setBlock replaces the block dst with a copy of src (see copyBlock). The light values of dst are kept.
+// Returns false, if dst already was the same block.
+func setBlock(dst *Block, src Block) bool {
+	if stateKey(*dst) == stateKey(src) && len(dst.TileEntity) == 0 && len(src.TileEntity) == 0 {
+		return false
+	}
+
+	src = copyBlock(src)
+	src.BlockLight, src.SkyLight = dst.BlockLight, dst.SkyLight
+	*dst = src
+	return true
+}
+
+// Fill sets all selected blocks to blk and returns the number of changed blocks. Blocks in unavailable chunks are skipped.
+// Blocks without a numeric ID are mapped like in Region.PasteSchematic, if that fails, UnmappableBlock is returned and
+// the chunks before Minecraft 1.13 are left unchanged.
+//
+// The light is not recalculated, use Region.RecalcLight for this.
+func (reg *Region) Fill(sel Selection, blk Block) (int, error) {
+	return reg.applySelection(sel, blk, setBlock)
+}
+
+// Replace replaces all selected blocks with the ID and data value of from (see BlockState.Legacy for blocks of Minecraft 1.13+)
+// by to and returns the number of changed blocks. See Fill.
+func (reg *Region) Replace(sel Selection, from, to Block) (int, error) {
+	return reg.applySelection(sel, to, func(dst *Block, to Block) bool {
+		return dst.ID == from.ID && dst.Data&0xf == from.Data&0xf && setBlock(dst, to)
+	})
+}
+
+// Walls sets the blocks on the sides (not top and bottom) of the selection to blk. See Fill.
+func (reg *Region) Walls(sel Selection, blk Block) (int, error) {
+	return reg.Fill(border{sel, []Facing{FacingNorth, FacingSouth, FacingWest, FacingEast}}, blk)
+}
+
+// Outline sets the blocks on the surface of the selection (including top and bottom) to blk. See Fill.
+func (reg *Region) Outline(sel Selection, blk Block) (int, error) {
+	return reg.Fill(border{sel, []Facing{FacingDown, FacingUp, FacingNorth, FacingSouth, FacingWest, FacingEast}}, blk)
+}
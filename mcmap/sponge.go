@@ -0,0 +1,383 @@
+package mcmap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"io"
+)
+
+// This file implements the Sponge schematic format (.schem), used by WorldEdit since Minecraft 1.13.
+//
+// Specification: https://github.com/SpongePowered/Schematic-Specification
+
+var (
+	UnknownSchematicVersion = errors.New("Unknown schematic version")
+)
+
+// Tags interpreted by ReadSpongeSchematic, indexed by version.
+var spongeTags = map[int32]map[string]bool{
+	1: {"Version": true, "Width": true, "Height": true, "Length": true, "PaletteMax": true, "Palette": true, "BlockData": true, "TileEntities": true},
+	2: {"Version": true, "DataVersion": true, "Width": true, "Height": true, "Length": true, "PaletteMax": true, "Palette": true, "BlockData": true, "BlockEntities": true, "Entities": true},
+	3: {"Version": true, "DataVersion": true, "Width": true, "Height": true, "Length": true, "Blocks": true, "Entities": true},
+}
+
+// Extra tags of one version, that have a different format in other versions.
+var spongeVersionTags = map[int32][]string{
+	2: {"BiomePaletteMax", "BiomePalette", "BiomeData"},
+	3: {"Biomes"},
+}
+
+// ReadSpongeSchematic reads a schematic in the Sponge format (version 1 to 3).
+// The blocks of the palette are converted with ParseBlockState and Block.SetState.
+func ReadSpongeSchematic(r io.Reader) (*Schematic, error) {
+	tag, _, err := nbt.ReadGzipdNamedTag(r)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Type != nbt.TAG_Compound {
+		return nil, errors.New("Root tag is not a TAG_Compound")
+	}
+	root := tag.Payload.(nbt.TagCompound)
+
+	// Since version 3, the schematic is wrapped in a compound.
+	if inner, err := root.GetCompound("Schematic"); err == nil {
+		root = inner
+	}
+
+	version, err := root.GetInt("Version")
+	if err != nil {
+		return nil, fmt.Errorf("Could not read Version tag: %s", err)
+	}
+	if version < 1 || version > 3 {
+		return nil, UnknownSchematicVersion
+	}
+
+	var size [3]int16
+	for i, name := range []string{"Width", "Height", "Length"} {
+		if size[i], err = root.GetShort(name); err != nil {
+			return nil, fmt.Errorf("Could not read %s tag: %s", name, err)
+		}
+	}
+	s := NewSchematic(int(uint16(size[0])), int(uint16(size[1])), int(uint16(size[2])))
+	s.extra, s.extraFormat = unknownTags(root, spongeTags[version]), formatSponge
+
+	s.DataVersion = dataVersionFlattening
+	if version >= 2 {
+		if s.DataVersion, err = root.GetInt("DataVersion"); err != nil {
+			return nil, fmt.Errorf("Could not read DataVersion tag: %s", err)
+		}
+	}
+
+	blocks, paletteName, dataName, tileEntsName := root, "Palette", "BlockData", "BlockEntities"
+	switch version {
+	case 1:
+		tileEntsName = "TileEntities"
+	case 3:
+		dataName = "Data"
+		if blocks, err = root.GetCompound("Blocks"); err != nil {
+			if err == nbt.NotFound {
+				// No blocks, only entities or biomes.
+				return s, s.readSpongeEntities(root, version)
+			}
+			return nil, fmt.Errorf("Could not read Blocks tag: %s", err)
+		}
+	}
+
+	if err := s.readSpongeBlocks(blocks, paletteName, dataName); err != nil {
+		return nil, err
+	}
+	if err := s.readSpongeTileEntities(blocks, tileEntsName, version); err != nil {
+		return nil, err
+	}
+	if err := s.readSpongeEntities(root, version); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Schematic) readSpongeBlocks(tc nbt.TagCompound, paletteName, dataName string) error {
+	paletteTag, err := tc.GetCompound(paletteName)
+	if err != nil {
+		return fmt.Errorf("Could not read %s tag: %s", paletteName, err)
+	}
+	palette := make(map[int]Block, len(paletteTag))
+	for str := range paletteTag {
+		idx, err := paletteTag.GetInt(str)
+		if err != nil {
+			return fmt.Errorf("Could not read %s tag: %s", paletteName, err)
+		}
+		bs, err := ParseBlockState(str)
+		if err != nil {
+			return fmt.Errorf("Could not read %s tag: %q: %s", paletteName, str, err)
+		}
+		var blk Block
+		blk.SetState(bs)
+		palette[int(idx)] = blk
+	}
+
+	data, err := tc.GetByteArray(dataName)
+	if err != nil {
+		return fmt.Errorf("Could not read %s tag: %s", dataName, err)
+	}
+	indices, err := readVarints(data, len(s.blocks))
+	if err != nil {
+		return fmt.Errorf("Could not read %s tag: %s", dataName, err)
+	}
+	for i, idx := range indices {
+		blk, ok := palette[idx]
+		if !ok {
+			return fmt.Errorf("Invalid palette index %d in %s", idx, dataName)
+		}
+		s.blocks[i] = blk
+	}
+
+	return nil
+}
+
+// spongePos reads the Pos tag of a block entity.
+func spongePos(tc nbt.TagCompound) (x, y, z int, err error) {
+	pos, err := tc.GetIntArray("Pos")
+	if err != nil {
+		return
+	}
+	if len(pos) != 3 {
+		err = errors.New("Pos does not have 3 elements")
+		return
+	}
+	return int(pos[0]), int(pos[1]), int(pos[2]), nil
+}
+
+// spongeData returns the NBT data of a block entity or entity with the given ID in the usual format.
+// Since version 3, the data is stored in a Data compound, before it was stored next to Id and Pos.
+func spongeData(tc nbt.TagCompound, version int32) (nbt.TagCompound, error) {
+	id, err := tc.GetString("Id")
+	if err == nbt.NotFound {
+		id, err = tc.GetString("id")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Could not read Id tag: %s", err)
+	}
+
+	var data nbt.TagCompound
+	if version >= 3 {
+		if data, err = tc.GetCompound("Data"); optional(err) != nil {
+			return nil, fmt.Errorf("Could not read Data tag: %s", err)
+		}
+		data = copyCompound(data)
+		if data == nil {
+			data = make(nbt.TagCompound)
+		}
+	} else {
+		data = copyCompound(tc)
+		delete(data, "Id")
+	}
+	data["id"] = nbt.Tag{nbt.TAG_String, id}
+	return data, nil
+}
+
+func (s *Schematic) readSpongeTileEntities(tc nbt.TagCompound, name string, version int32) error {
+	tileEnts, err := tc.GetList(name)
+	if err != nil || tileEnts.Type != nbt.TAG_Compound {
+		return optional(err)
+	}
+
+	for _, elem := range tileEnts.Elems {
+		x, y, z, err := spongePos(elem.(nbt.TagCompound))
+		if err != nil {
+			return fmt.Errorf("Could not read %s tag: %s", name, err)
+		}
+		te, err := spongeData(elem.(nbt.TagCompound), version)
+		if err != nil {
+			return fmt.Errorf("Could not read %s tag: %s", name, err)
+		}
+		delete(te, "Pos")
+		te["x"] = nbt.NewIntTag(int32(x))
+		te["y"] = nbt.NewIntTag(int32(y))
+		te["z"] = nbt.NewIntTag(int32(z))
+
+		if blk := s.Block(x, y, z); blk != nil {
+			blk.TileEntity = te
+		}
+	}
+	return nil
+}
+
+func (s *Schematic) readSpongeEntities(root nbt.TagCompound, version int32) error {
+	ents, err := root.GetList("Entities")
+	if err != nil || ents.Type != nbt.TAG_Compound {
+		return optional(err)
+	}
+
+	for _, elem := range ents.Elems {
+		tc, err := spongeData(elem.(nbt.TagCompound), version)
+		if err != nil {
+			return fmt.Errorf("Could not read Entities tag: %s", err)
+		}
+		if pos, ok := elem.(nbt.TagCompound)["Pos"]; ok {
+			tc["Pos"] = pos
+		}
+		ent, err := EntityFromNBT(tc)
+		if err != nil {
+			return fmt.Errorf("Could not read Entities tag: %s", err)
+		}
+		s.Entities = append(s.Entities, ent)
+	}
+	return nil
+}
+
+// WriteSponge writes the schematic in the Sponge format of the given version (2 or 3).
+// Schematics with a data version before Minecraft 1.13 are converted to block states (see Block.BlockState).
+func (s *Schematic) WriteSponge(w io.Writer, version int32) error {
+	if version != 2 && version != 3 {
+		return UnknownSchematicVersion
+	}
+
+	dataVersion := s.DataVersion
+	if dataVersion < dataVersionFlattening {
+		dataVersion = dataVersionFlattening
+	}
+
+	root := s.extraTags(formatSponge)
+	for v, names := range spongeVersionTags {
+		if v != version {
+			for _, name := range names {
+				delete(root, name)
+			}
+		}
+	}
+
+	root["Version"] = nbt.NewIntTag(version)
+	root["DataVersion"] = nbt.NewIntTag(dataVersion)
+	root["Width"] = nbt.NewShortTag(int16(s.Width))
+	root["Height"] = nbt.NewShortTag(int16(s.Height))
+	root["Length"] = nbt.NewShortTag(int16(s.Length))
+
+	palette, data := s.spongePalette()
+	tileEnts := s.spongeTileEntities(version)
+	ents := s.spongeEntities(version, dataVersion)
+
+	if version == 3 {
+		root["Blocks"] = nbt.Tag{nbt.TAG_Compound, nbt.TagCompound{
+			"Palette":       nbt.Tag{nbt.TAG_Compound, palette},
+			"Data":          nbt.NewByteArrayTag(data),
+			"BlockEntities": nbt.NewListTag(nbt.TAG_Compound, tileEnts),
+		}}
+		root["Entities"] = nbt.NewListTag(nbt.TAG_Compound, ents)
+		return nbt.WriteGzipdNamedTag(w, "", nbt.Tag{nbt.TAG_Compound, nbt.TagCompound{
+			"Schematic": nbt.Tag{nbt.TAG_Compound, root},
+		}})
+	}
+
+	root["PaletteMax"] = nbt.NewIntTag(int32(len(palette)))
+	root["Palette"] = nbt.Tag{nbt.TAG_Compound, palette}
+	root["BlockData"] = nbt.NewByteArrayTag(data)
+	root["BlockEntities"] = nbt.NewListTag(nbt.TAG_Compound, tileEnts)
+	root["Entities"] = nbt.NewListTag(nbt.TAG_Compound, ents)
+	return nbt.WriteGzipdNamedTag(w, "Schematic", nbt.Tag{nbt.TAG_Compound, root})
+}
+
+// spongePalette creates the palette and the varint encoded block data.
+func (s *Schematic) spongePalette() (nbt.TagCompound, []byte) {
+	palette := make(nbt.TagCompound)
+	indices := make(map[blockKey]int)
+	data := make([]byte, 0, len(s.blocks))
+	for _, blk := range s.blocks {
+		key := stateKey(blk)
+		idx, ok := indices[key]
+		if !ok {
+			str := blk.BlockState().String()
+			if tag, ok := palette[str]; ok {
+				idx = int(tag.Payload.(int32))
+			} else {
+				idx = len(palette)
+				palette[str] = nbt.NewIntTag(int32(idx))
+			}
+			indices[key] = idx
+		}
+		data = appendVarint(data, idx)
+	}
+	return palette, data
+}
+
+func (s *Schematic) spongeTileEntities(version int32) []nbt.TagCompound {
+	tileEnts := s.tileEntities()
+	for i, te := range tileEnts {
+		x, y, z, _ := extractCoord(te)
+		convertTileEntityID(te, s.blocks[s.blockOffset(x, y, z)].ID, s.DataVersion, dataVersionFlattening)
+		id, _ := te.GetString("id")
+		for _, name := range []string{"id", "x", "y", "z"} {
+			delete(te, name)
+		}
+
+		tc := nbt.TagCompound{
+			"Id":  nbt.Tag{nbt.TAG_String, id},
+			"Pos": nbt.NewIntArrayTag([]int32{int32(x), int32(y), int32(z)}),
+		}
+		if version >= 3 {
+			tc["Data"] = nbt.Tag{nbt.TAG_Compound, te}
+		} else {
+			for k, v := range te {
+				tc[k] = v
+			}
+		}
+		tileEnts[i] = tc
+	}
+	return tileEnts
+}
+
+func (s *Schematic) spongeEntities(version, dataVersion int32) []nbt.TagCompound {
+	ents := make([]nbt.TagCompound, len(s.Entities))
+	for i, ent := range s.Entities {
+		ent = ent.translated(0, 0, 0)
+		convertEntityID(ent, s.DataVersion, dataVersion)
+		data := ent.toNBT(dataVersion)
+		tc := nbt.TagCompound{
+			"Id":  data["id"],
+			"Pos": data["Pos"],
+		}
+		if version >= 3 {
+			tc["Data"] = nbt.Tag{nbt.TAG_Compound, data}
+		} else {
+			for k, v := range data {
+				if k != "id" {
+					tc[k] = v
+				}
+			}
+		}
+		ents[i] = tc
+	}
+	return ents
+}
+
+// readVarints decodes n varint encoded values.
+func readVarints(data []byte, n int) ([]int, error) {
+	vals := make([]int, 0, n)
+	v, shift := 0, uint(0)
+	for _, b := range data {
+		v |= int(b&0x7f) << shift
+		if b&0x80 != 0 {
+			shift += 7
+			if shift > 28 {
+				return nil, errors.New("Varint too long")
+			}
+			continue
+		}
+		vals = append(vals, v)
+		v, shift = 0, 0
+	}
+
+	if shift != 0 || len(vals) != n {
+		return nil, fmt.Errorf("Expected %d values, got %d", n, len(vals))
+	}
+	return vals, nil
+}
+
+func appendVarint(buf []byte, v int) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v&0x7f)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
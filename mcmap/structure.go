@@ -0,0 +1,271 @@
+package mcmap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"io"
+	"math"
+)
+
+// This file implements the structure files of structure blocks (structures/*.nbt), available since Minecraft 1.10.
+
+var (
+	UnsupportedStructure = errors.New("Structures from versions before Minecraft 1.13 are not supported")
+)
+
+// Root tags interpreted by ReadStructure.
+var structureTags = map[string]bool{
+	"DataVersion": true,
+	"size":        true,
+	"palette":     true,
+	"palettes":    true,
+	"blocks":      true,
+	"entities":    true,
+}
+
+var structureVoid = &BlockState{Name: "minecraft:structure_void"}
+
+// ReadStructure reads a structure file, as saved by structure blocks.
+// Positions not contained in the structure are filled with structure void blocks, see PasteStructure.
+// If the structure has multiple palettes (e.g. shipwrecks), the first one is used.
+func ReadStructure(r io.Reader) (*Schematic, error) {
+	tag, _, err := nbt.ReadGzipdNamedTag(r)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Type != nbt.TAG_Compound {
+		return nil, errors.New("Root tag is not a TAG_Compound")
+	}
+	root := tag.Payload.(nbt.TagCompound)
+
+	dataVersion, err := root.GetInt("DataVersion")
+	if err != nil {
+		return nil, fmt.Errorf("Could not read DataVersion tag: %s", err)
+	}
+	if dataVersion < dataVersionFlattening {
+		return nil, UnsupportedStructure
+	}
+
+	size, err := getIntList(root, "size", 3)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read size tag: %s", err)
+	}
+	s := NewSchematic(size[0], size[1], size[2])
+	s.DataVersion = dataVersion
+	s.extra, s.extraFormat = unknownTags(root, structureTags), formatStructure
+
+	palette, err := readStructurePalette(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var void Block
+	void.SetState(structureVoid)
+	for i := range s.blocks {
+		s.blocks[i] = void
+	}
+
+	if err := s.readStructureBlocks(root, palette); err != nil {
+		return nil, err
+	}
+	if err := s.readStructureEntities(root); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func getIntList(tc nbt.TagCompound, name string, n int) ([]int, error) {
+	list, err := tc.GetList(name)
+	if err != nil {
+		return nil, err
+	}
+	if list.Type != nbt.TAG_Int || len(list.Elems) != n {
+		return nil, fmt.Errorf("Not a list of %d TAG_Int", n)
+	}
+	ints := make([]int, n)
+	for i, elem := range list.Elems {
+		ints[i] = int(elem.(int32))
+	}
+	return ints, nil
+}
+
+func intList(ints ...int) nbt.Tag {
+	list := make([]int32, len(ints))
+	for i, v := range ints {
+		list[i] = int32(v)
+	}
+	return nbt.NewListTag(nbt.TAG_Int, list)
+}
+
+func readStructurePalette(root nbt.TagCompound) ([]Block, error) {
+	list, err := root.GetList("palette")
+	if err == nbt.NotFound {
+		var palettes nbt.TagList
+		if palettes, err = root.GetList("palettes"); err == nil {
+			if palettes.Type != nbt.TAG_List || len(palettes.Elems) == 0 {
+				return nil, errors.New("Could not read palettes tag: Not a non-empty list of lists")
+			}
+			list = palettes.Elems[0].(nbt.TagList)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Could not read palette tag: %s", err)
+	}
+	if list.Type != nbt.TAG_Compound && len(list.Elems) > 0 {
+		return nil, errors.New("Could not read palette tag: Not a list of TAG_Compound")
+	}
+
+	palette := make([]Block, len(list.Elems))
+	for i, elem := range list.Elems {
+		bs, err := blockStateFromNBT(elem.(nbt.TagCompound))
+		if err != nil {
+			return nil, fmt.Errorf("Could not read palette tag: %s", err)
+		}
+		palette[i].SetState(bs)
+	}
+	return palette, nil
+}
+
+func (s *Schematic) readStructureBlocks(root nbt.TagCompound, palette []Block) error {
+	blocks, err := root.GetList("blocks")
+	if err != nil || blocks.Type != nbt.TAG_Compound {
+		return optional(err)
+	}
+
+	for _, elem := range blocks.Elems {
+		tc := elem.(nbt.TagCompound)
+		pos, err := getIntList(tc, "pos", 3)
+		if err != nil {
+			return fmt.Errorf("Could not read blocks -> pos tag: %s", err)
+		}
+		state, err := tc.GetInt("state")
+		if err != nil {
+			return fmt.Errorf("Could not read blocks -> state tag: %s", err)
+		}
+		if state < 0 || int(state) >= len(palette) {
+			return fmt.Errorf("Invalid palette index %d in blocks", state)
+		}
+
+		blk := s.Block(pos[0], pos[1], pos[2])
+		if blk == nil {
+			continue
+		}
+		*blk = palette[state]
+
+		if te, err := tc.GetCompound("nbt"); err == nil {
+			te = copyCompound(te)
+			te["x"] = nbt.NewIntTag(int32(pos[0]))
+			te["y"] = nbt.NewIntTag(int32(pos[1]))
+			te["z"] = nbt.NewIntTag(int32(pos[2]))
+			blk.TileEntity = te
+		}
+	}
+	return nil
+}
+
+func (s *Schematic) readStructureEntities(root nbt.TagCompound) error {
+	ents, err := root.GetList("entities")
+	if err != nil || ents.Type != nbt.TAG_Compound {
+		return optional(err)
+	}
+
+	for _, elem := range ents.Elems {
+		tc := elem.(nbt.TagCompound)
+		data, err := tc.GetCompound("nbt")
+		if err != nil {
+			return fmt.Errorf("Could not read entities -> nbt tag: %s", err)
+		}
+		data = copyCompound(data)
+		if pos, ok := tc["pos"]; ok {
+			data["Pos"] = pos
+		}
+
+		ent, err := EntityFromNBT(data)
+		if err != nil {
+			return fmt.Errorf("Could not read entities tag: %s", err)
+		}
+		s.Entities = append(s.Entities, ent)
+	}
+	return nil
+}
+
+// WriteStructure writes the schematic as a structure file. Structure void blocks are not included in the structure.
+// Schematics with a data version before Minecraft 1.13 are converted to block states (see Block.BlockState).
+func (s *Schematic) WriteStructure(w io.Writer) error {
+	dataVersion := s.DataVersion
+	if dataVersion < dataVersionFlattening {
+		dataVersion = dataVersionFlattening
+	}
+
+	root := s.extraTags(formatStructure)
+	root["DataVersion"] = nbt.NewIntTag(dataVersion)
+	root["size"] = intList(s.Width, s.Height, s.Length)
+	delete(root, "palettes")
+
+	var palette, blocks []nbt.TagCompound
+	indices := make(map[blockKey]int)
+	for off, blk := range s.blocks {
+		key := stateKey(blk)
+		idx, ok := indices[key]
+		if !ok {
+			bs := blk.BlockState()
+			if bs.Name == structureVoid.Name {
+				idx = -1
+			} else {
+				idx = len(palette)
+				palette = append(palette, bs.toNBT())
+			}
+			indices[key] = idx
+		}
+		if idx < 0 {
+			continue
+		}
+
+		x, y, z := off%s.Width, off/(s.Width*s.Length), (off/s.Width)%s.Length
+		tc := nbt.TagCompound{
+			"pos":   intList(x, y, z),
+			"state": nbt.NewIntTag(int32(idx)),
+		}
+		if len(blk.TileEntity) > 0 {
+			te := copyCompound(blk.TileEntity)
+			convertTileEntityID(te, blk.ID, s.DataVersion, dataVersion)
+			for _, name := range []string{"x", "y", "z"} {
+				delete(te, name)
+			}
+			tc["nbt"] = nbt.Tag{nbt.TAG_Compound, te}
+		}
+		blocks = append(blocks, tc)
+	}
+	root["palette"] = nbt.NewListTag(nbt.TAG_Compound, palette)
+	root["blocks"] = nbt.NewListTag(nbt.TAG_Compound, blocks)
+
+	ents := make([]nbt.TagCompound, len(s.Entities))
+	for i, ent := range s.Entities {
+		ent = ent.translated(0, 0, 0)
+		convertEntityID(ent, s.DataVersion, dataVersion)
+		data := ent.toNBT(dataVersion)
+		ents[i] = nbt.TagCompound{
+			"pos":      data["Pos"],
+			"blockPos": intList(int(math.Floor(ent.Pos[0])), int(math.Floor(ent.Pos[1])), int(math.Floor(ent.Pos[2]))),
+			"nbt":      nbt.Tag{nbt.TAG_Compound, data},
+		}
+	}
+	root["entities"] = nbt.NewListTag(nbt.TAG_Compound, ents)
+
+	return nbt.WriteGzipdNamedTag(w, "", nbt.Tag{nbt.TAG_Compound, root})
+}
+
+// PasteStructure pastes a structure like a structure block does: The structure is mirrored and then rotated (see Schematic.Transformed),
+// the lowest corner of the transformed structure is placed at x, y, z. Structure void blocks are not pasted, the blocks of the region
+// stay unchanged there. See PasteSchematic for the handling of unavailable chunks, entities and unmappable blocks.
+func (reg *Region) PasteStructure(s *Schematic, x, y, z int, rot Rotation, mirror Mirror) (unmapped []string, err error) {
+	if rot != RotationNone || mirror != MirrorNone {
+		s = s.Transformed(rot, mirror)
+	}
+
+	return reg.pasteBlocks(s, x, y, z, func(sx, sy, sz int, blk *Block) (Block, bool) {
+		return *blk, blk.ID != BlkStructureVoid
+	})
+}
@@ -0,0 +1,98 @@
+package mcmap
+
+import (
+	"errors"
+)
+
+var (
+	OutsideOfChunk = errors.New("Position is outside of the chunk")
+)
+
+// Names of the fluids with scheduled fluid updates (Minecraft 1.13+).
+var fluidNames = map[string]bool{
+	"minecraft:water":         true,
+	"minecraft:flowing_water": true,
+	"minecraft:lava":          true,
+	"minecraft:flowing_lava":  true,
+}
+
+// ScheduledTick is a scheduled block or fluid update of a chunk.
+type ScheduledTick struct {
+	X, Y, Z int  // Position in the chunk
+	Fluid   bool // Update of a fluid (Minecraft 1.13+, before fluids are updated like blocks)
+	*TileTick
+}
+
+// ScheduledTicks returns all scheduled block and fluid updates of the chunk.
+// The block updates are the Tick fields of the blocks, changes to the TileTicks are saved, if the chunk is marked as modified.
+func (c *Chunk) ScheduledTicks() []ScheduledTick {
+	var ticks []ScheduledTick
+	for off := range c.blocks {
+		if tick := c.blocks[off].Tick; tick != nil {
+			x, y, z := c.offsetToPos(off)
+			ticks = append(ticks, ScheduledTick{x, y, z, false, tick})
+		}
+	}
+	for off, tick := range c.fluidTicks {
+		x, y, z := c.offsetToPos(off)
+		ticks = append(ticks, ScheduledTick{x, y, z, true, tick})
+	}
+	return ticks
+}
+
+// ScheduleTick schedules an update of the block at x, y, z (chunk coordinates) in delay ticks and marks the chunk as modified.
+// block is the name of the block to update (usually the name of the block at that position). In chunks of Minecraft 1.13+,
+// updates of water and lava are scheduled as fluid updates. A previously scheduled update of the block (or fluid) is replaced.
+//
+// Chunks before Minecraft 1.13 store the block by name, if they have a data version (1.9+) or their scheduled updates were
+// read with names (1.8), otherwise by numeric ID.
+func (c *Chunk) ScheduleTick(x, y, z int, block string, delay, priority int32) error {
+	off := c.blockOffset(x, y, z)
+	if off < 0 {
+		return OutsideOfChunk
+	}
+
+	blocks := DefaultBlocks
+	if c.reg != nil {
+		blocks = c.reg.Blocks()
+	}
+
+	tick := NewTileTick(block, blocks, delay, priority)
+	if c.dataVersion < dataVersionFlattening {
+		id, err := blocks.ParseBlockID(block)
+		if err != nil {
+			return err
+		}
+		tick.i = int32(id)
+		tick.name = ""
+		if c.dataVersion > 0 || c.stringTickIDs {
+			tick.name, _ = blocks.Name(id)
+		}
+	} else if id, _ := NewBlockState(tick.name, nil).Legacy(); id != 0 {
+		tick.i = int32(id)
+	}
+
+	if c.dataVersion >= dataVersionFlattening && fluidNames[tick.name] {
+		if c.fluidTicks == nil {
+			c.fluidTicks = make(map[int]*TileTick)
+		}
+		c.fluidTicks[off] = tick
+	} else {
+		c.blocks[off].Tick = tick
+	}
+
+	c.MarkModified()
+	return nil
+}
+
+// UnscheduleTicks removes the scheduled block and fluid updates at x, y, z (chunk coordinates) and marks the chunk as modified.
+func (c *Chunk) UnscheduleTicks(x, y, z int) {
+	off := c.blockOffset(x, y, z)
+	if off < 0 {
+		return
+	}
+
+	c.blocks[off].Tick = nil
+	delete(c.fluidTicks, off)
+	c.MarkModified()
+}
@@ -0,0 +1,117 @@
+package mcmap
+
+import (
+	"github.com/silvasur/gonbt/nbt"
+)
+
+// Tile entity IDs since Minecraft 1.11 (without namespace) and their equivalents before.
+var legacyTileEntityIDs = make(map[string]string)
+
+func init() {
+	for old, id := range oldTileEntityIDs {
+		legacyTileEntityIDs[id] = old
+	}
+	legacyTileEntityIDs["trapped_chest"] = "Chest"
+}
+
+// tileEntityID returns the ID of a tile entity (e.g. "chest") in the format of the given data version.
+func tileEntityID(name string, dataVersion int32) string {
+	name = stripNamespace(name)
+	if dataVersion < dataVersionNamespacedIDs {
+		if old, ok := legacyTileEntityIDs[name]; ok {
+			return old
+		}
+	}
+	return "minecraft:" + name
+}
+
+// TileEntityID returns the ID of a tile entity in the format of Minecraft 1.11+ (e.g. "minecraft:chest"), also for older tile entities.
+// Returns an empty string, if te has no ID.
+func TileEntityID(te nbt.TagCompound) string {
+	id, err := te.GetString("id")
+	if err != nil {
+		return ""
+	}
+	return upgradeTileEntityID(id, 0)
+}
+
+// newTileEntity creates the compound of a tile entity with the given ID (e.g. "chest").
+func newTileEntity(name string, dataVersion int32) tileEntity {
+	return tileEntity{
+		"id": nbt.Tag{nbt.TAG_String, tileEntityID(name, dataVersion)},
+		"x":  nbt.NewIntTag(0), // The coordinates are set, when the chunk is saved.
+		"y":  nbt.NewIntTag(0),
+		"z":  nbt.NewIntTag(0),
+	}
+}
+
+// tileEntity provides access to the tags of a tile entity for the views of the tile entities.
+type tileEntity nbt.TagCompound
+
+// NBT returns the tile entity compound.
+func (te tileEntity) NBT() nbt.TagCompound { return nbt.TagCompound(te) }
+
+func (te tileEntity) getByte(name string) byte {
+	v, _ := nbt.TagCompound(te).GetByte(name)
+	return v
+}
+
+func (te tileEntity) getShort(name string) int16 {
+	v, _ := nbt.TagCompound(te).GetShort(name)
+	return v
+}
+
+func (te tileEntity) getInt(name string) int32 {
+	v, _ := nbt.TagCompound(te).GetInt(name)
+	return v
+}
+
+func (te tileEntity) getString(name string) string {
+	v, _ := nbt.TagCompound(te).GetString(name)
+	return v
+}
+
+func (te tileEntity) setByte(name string, v byte)     { te[name] = nbt.NewByteTag(v) }
+func (te tileEntity) setShort(name string, v int16)   { te[name] = nbt.NewShortTag(v) }
+func (te tileEntity) setInt(name string, v int32)     { te[name] = nbt.NewIntTag(v) }
+func (te tileEntity) setString(name string, v string) { te[name] = nbt.Tag{nbt.TAG_String, v} }
+
+func (te tileEntity) getFlag(name string) bool { return te.getByte(name) != 0 }
+
+func (te tileEntity) setFlag(name string, v bool) {
+	b := byte(0)
+	if v {
+		b = 1
+	}
+	te.setByte(name, b)
+}
+
+func (te tileEntity) getCompound(name string) nbt.TagCompound {
+	tc, _ := nbt.TagCompound(te).GetCompound(name)
+	return tc
+}
+
+// setCompound sets a compound tag. A nil compound removes the tag.
+func (te tileEntity) setCompound(name string, tc nbt.TagCompound) {
+	if tc == nil {
+		delete(te, name)
+		return
+	}
+	te[name] = nbt.Tag{nbt.TAG_Compound, tc}
+}
+
+func (te tileEntity) getCompoundList(name string) []nbt.TagCompound {
+	list, err := nbt.TagCompound(te).GetList(name)
+	if err != nil || list.Type != nbt.TAG_Compound {
+		return nil
+	}
+	tcs := make([]nbt.TagCompound, len(list.Elems))
+	for i, elem := range list.Elems {
+		tcs[i] = elem.(nbt.TagCompound)
+	}
+	return tcs
+}
+
+func (te tileEntity) setCompoundList(name string, tcs []nbt.TagCompound) {
+	te[name] = nbt.NewListTag(nbt.TAG_Compound, tcs)
+}
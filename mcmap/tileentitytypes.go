@@ -0,0 +1,475 @@
+package mcmap
+
+import (
+	"encoding/json"
+	"github.com/silvasur/gonbt/nbt"
+)
+
+// This file contains views of tile entities, giving typed access to the tags of some kinds of tile entities.
+// The views work on the compound of the tile entity (Block.TileEntity), so changes are saved together with the block.
+// The constructors create the compound of a new tile entity in the format of the given data version (see Chunk.DataVersion).
+
+// Number of slots of the containers.
+var containerSizes = map[string]int{
+	"chest":         27,
+	"trapped_chest": 27,
+	"barrel":        27,
+	"shulker_box":   27,
+	"dispenser":     9,
+	"dropper":       9,
+	"hopper":        5,
+	"brewing_stand": 5,
+	"furnace":       3,
+	"blast_furnace": 3,
+	"smoker":        3,
+}
+
+// Container is a view of a tile entity with an inventory (chests, dispensers, hoppers, furnaces, ...).
+type Container struct{ tileEntity }
+
+// AsContainer returns the container view of a tile entity.
+func AsContainer(te nbt.TagCompound) Container { return Container{tileEntity(te)} }
+
+// NewContainer creates an empty container. name is the tile entity ID of Minecraft 1.11+, e.g. "chest" or "minecraft:hopper".
+func NewContainer(name string, dataVersion int32) nbt.TagCompound {
+	te := newTileEntity(name, dataVersion)
+	te.setCompoundList("Items", nil)
+	return te.NBT()
+}
+
+// Size returns the number of slots of the container (0, if the container type is unknown).
+func (c Container) Size() int { return containerSizes[stripNamespace(TileEntityID(c.NBT()))] }
+
+// Inventory returns the inventory of the container.
+func (c Container) Inventory() Inventory { return slotInventory{c.NBT(), "Items", c.Size()} }
+
+// CustomName returns the name of the container shown in the GUI (a JSON text component since Minecraft 1.13). Empty, if not set.
+func (c Container) CustomName() string { return c.getString("CustomName") }
+
+func (c Container) SetCustomName(name string) {
+	if name == "" {
+		delete(c.tileEntity, "CustomName")
+		return
+	}
+	c.setString("CustomName", name)
+}
+
+// Lock returns the name an item must have to open the container. Empty, if the container is not locked.
+func (c Container) Lock() string { return c.getString("Lock") }
+
+func (c Container) SetLock(lock string) {
+	if lock == "" {
+		delete(c.tileEntity, "Lock")
+		return
+	}
+	c.setString("Lock", lock)
+}
+
+// Sign is a view of a sign. Since Minecraft 1.20, signs have text on both sides.
+type Sign struct{ tileEntity }
+
+// AsSign returns the sign view of a tile entity.
+func AsSign(te nbt.TagCompound) Sign { return Sign{tileEntity(te)} }
+
+// NewSign creates a sign with the given plain text lines on the front side.
+func NewSign(dataVersion int32, text [4]string) nbt.TagCompound {
+	s := Sign{newTileEntity("sign", dataVersion)}
+	if dataVersion >= dataVersionSignSides {
+		for _, side := range []string{"front_text", "back_text"} {
+			s.setCompound(side, nbt.TagCompound{
+				"messages":         nbt.NewListTag(nbt.TAG_String, []string{`""`, `""`, `""`, `""`}),
+				"color":            nbt.Tag{nbt.TAG_String, "black"},
+				"has_glowing_text": nbt.NewByteTag(0),
+			})
+		}
+		s.setByte("is_waxed", 0)
+	}
+	s.SetText(text)
+	return s.NBT()
+}
+
+func (s Sign) twoSided() bool {
+	_, ok := s.tileEntity["front_text"]
+	return ok
+}
+
+func (s Sign) lines(side string) (lines [4]string) {
+	if !s.twoSided() {
+		if side == "front_text" {
+			for i := range lines {
+				lines[i] = s.getString(textLineTags[i])
+			}
+		}
+		return
+	}
+
+	list, err := s.getCompound(side).GetList("messages")
+	if err != nil || list.Type != nbt.TAG_String {
+		return
+	}
+	for i := 0; i < len(lines) && i < len(list.Elems); i++ {
+		lines[i] = list.Elems[i].(string)
+	}
+	return
+}
+
+func (s Sign) setLines(side string, lines [4]string) {
+	if !s.twoSided() {
+		if side == "front_text" {
+			for i, line := range lines {
+				s.setString(textLineTags[i], line)
+			}
+		}
+		return
+	}
+
+	text := s.getCompound(side)
+	if text == nil {
+		text = make(nbt.TagCompound)
+		s.setCompound(side, text)
+	}
+	text["messages"] = nbt.NewListTag(nbt.TAG_String, lines[:])
+}
+
+var textLineTags = [4]string{"Text1", "Text2", "Text3", "Text4"}
+
+// Lines returns the lines of the front side as stored (JSON text components since Minecraft 1.8).
+func (s Sign) Lines() [4]string         { return s.lines("front_text") }
+func (s Sign) SetLines(lines [4]string) { s.setLines("front_text", lines) }
+
+// BackLines returns the lines of the back side (Minecraft 1.20+, empty for older signs).
+func (s Sign) BackLines() [4]string { return s.lines("back_text") }
+
+// SetBackLines sets the lines of the back side. This has no effect on signs from versions before Minecraft 1.20.
+func (s Sign) SetBackLines(lines [4]string) { s.setLines("back_text", lines) }
+
+// Text returns the plain text of the lines of the front side, without formatting.
+func (s Sign) Text() (text [4]string) {
+	for i, line := range s.Lines() {
+		text[i] = plainText(line)
+	}
+	return
+}
+
+// SetText sets the lines of the front side to plain text (stored as JSON text components).
+func (s Sign) SetText(text [4]string) {
+	var lines [4]string
+	for i, t := range text {
+		buf, _ := json.Marshal(t)
+		lines[i] = string(buf)
+	}
+	s.SetLines(lines)
+}
+
+// Color returns the dye color of the text on the front side (Minecraft 1.14+, e.g. "black").
+func (s Sign) Color() string {
+	if s.twoSided() {
+		color, _ := s.getCompound("front_text").GetString("color")
+		return color
+	}
+	return s.getString("Color")
+}
+
+func (s Sign) SetColor(color string) {
+	if s.twoSided() {
+		s.getCompound("front_text")["color"] = nbt.Tag{nbt.TAG_String, color}
+		return
+	}
+	s.setString("Color", color)
+}
+
+// Glowing checks, if the text on the front side glows (Minecraft 1.17+).
+func (s Sign) Glowing() bool {
+	if s.twoSided() {
+		b, _ := s.getCompound("front_text").GetByte("has_glowing_text")
+		return b != 0
+	}
+	return s.getFlag("GlowingText")
+}
+
+func (s Sign) SetGlowing(glowing bool) {
+	if s.twoSided() {
+		tileEntity(s.getCompound("front_text")).setFlag("has_glowing_text", glowing)
+		return
+	}
+	s.setFlag("GlowingText", glowing)
+}
+
+// MobSpawner is a view of a monster spawner.
+type MobSpawner struct{ tileEntity }
+
+// AsMobSpawner returns the mob spawner view of a tile entity.
+func AsMobSpawner(te nbt.TagCompound) MobSpawner { return MobSpawner{tileEntity(te)} }
+
+// NewMobSpawner creates a spawner of the given entity type (e.g. "minecraft:zombie", "Zombie" before Minecraft 1.11)
+// with the default settings of Minecraft.
+func NewMobSpawner(entityID string, dataVersion int32) nbt.TagCompound {
+	s := MobSpawner{newTileEntity("mob_spawner", dataVersion)}
+	switch {
+	case dataVersion >= dataVersionSpawnerEntity:
+		s.setCompound("SpawnData", nbt.TagCompound{"entity": nbt.Tag{nbt.TAG_Compound, make(nbt.TagCompound)}})
+	case dataVersion > 0:
+		s.setCompound("SpawnData", make(nbt.TagCompound))
+	default:
+		s.setString("EntityId", "")
+	}
+	s.SetEntity(entityID)
+
+	s.setShort("Delay", 20)
+	s.setShort("MinSpawnDelay", 200)
+	s.setShort("MaxSpawnDelay", 800)
+	s.setShort("SpawnCount", 4)
+	s.setShort("SpawnRange", 4)
+	s.setShort("MaxNearbyEntities", 6)
+	s.setShort("RequiredPlayerRange", 16)
+	return s.NBT()
+}
+
+// Entity returns the type of the spawned entity.
+func (s MobSpawner) Entity() string {
+	spawnData := s.getCompound("SpawnData")
+	if entity, err := spawnData.GetCompound("entity"); err == nil {
+		id, _ := entity.GetString("id")
+		return id
+	}
+	if id, err := spawnData.GetString("id"); err == nil {
+		return id
+	}
+	return s.getString("EntityId")
+}
+
+// SetEntity sets the type of the spawned entity. The other spawn potentials are removed.
+func (s MobSpawner) SetEntity(id string) {
+	if _, ok := s.tileEntity["EntityId"]; ok {
+		// Before Minecraft 1.9.
+		s.setString("EntityId", id)
+		delete(s.tileEntity, "SpawnData")
+		delete(s.tileEntity, "SpawnPotentials")
+		return
+	}
+
+	entity := nbt.TagCompound{"id": nbt.Tag{nbt.TAG_String, id}}
+	if _, err := s.getCompound("SpawnData").GetCompound("entity"); err == nil {
+		s.setCompound("SpawnData", nbt.TagCompound{"entity": nbt.Tag{nbt.TAG_Compound, entity}})
+	} else {
+		s.setCompound("SpawnData", entity)
+	}
+	s.setCompoundList("SpawnPotentials", nil)
+}
+
+// Delay returns the number of ticks until the next spawn.
+func (s MobSpawner) Delay() int16         { return s.getShort("Delay") }
+func (s MobSpawner) SetDelay(delay int16) { s.setShort("Delay", delay) }
+
+// SpawnDelays returns the range of the random delay after spawning.
+func (s MobSpawner) SpawnDelays() (min, max int16) {
+	return s.getShort("MinSpawnDelay"), s.getShort("MaxSpawnDelay")
+}
+
+func (s MobSpawner) SetSpawnDelays(min, max int16) {
+	s.setShort("MinSpawnDelay", min)
+	s.setShort("MaxSpawnDelay", max)
+}
+
+// SpawnCount returns the number of entities spawned at once.
+func (s MobSpawner) SpawnCount() int16         { return s.getShort("SpawnCount") }
+func (s MobSpawner) SetSpawnCount(count int16) { s.setShort("SpawnCount", count) }
+
+// SpawnRange returns the maximum horizontal distance of spawned entities to the spawner.
+func (s MobSpawner) SpawnRange() int16     { return s.getShort("SpawnRange") }
+func (s MobSpawner) SetSpawnRange(r int16) { s.setShort("SpawnRange", r) }
+
+// RequiredPlayerRange returns the distance a player must be within to activate the spawner.
+func (s MobSpawner) RequiredPlayerRange() int16     { return s.getShort("RequiredPlayerRange") }
+func (s MobSpawner) SetRequiredPlayerRange(r int16) { s.setShort("RequiredPlayerRange", r) }
+
+// MaxNearbyEntities returns the number of nearby entities of the spawned type, that stop the spawner.
+func (s MobSpawner) MaxNearbyEntities() int16     { return s.getShort("MaxNearbyEntities") }
+func (s MobSpawner) SetMaxNearbyEntities(n int16) { s.setShort("MaxNearbyEntities", n) }
+
+// CommandBlock is a view of a command block.
+type CommandBlock struct{ tileEntity }
+
+// AsCommandBlock returns the command block view of a tile entity.
+func AsCommandBlock(te nbt.TagCompound) CommandBlock { return CommandBlock{tileEntity(te)} }
+
+// NewCommandBlock creates a command block with the given command.
+func NewCommandBlock(command string, dataVersion int32) nbt.TagCompound {
+	cb := CommandBlock{newTileEntity("command_block", dataVersion)}
+	cb.SetCommand(command)
+	cb.setInt("SuccessCount", 0)
+	cb.setFlag("TrackOutput", true)
+	cb.setFlag("powered", false)
+	cb.setFlag("auto", false)
+	cb.setFlag("conditionMet", false)
+	return cb.NBT()
+}
+
+func (cb CommandBlock) Command() string           { return cb.getString("Command") }
+func (cb CommandBlock) SetCommand(command string) { cb.setString("Command", command) }
+
+// LastOutput returns the output of the last execution (a JSON text component), if TrackOutput is set.
+func (cb CommandBlock) LastOutput() string { return cb.getString("LastOutput") }
+
+// SuccessCount returns the success count of the last execution, as read by comparators.
+func (cb CommandBlock) SuccessCount() int32 { return cb.getInt("SuccessCount") }
+
+func (cb CommandBlock) TrackOutput() bool     { return cb.getFlag("TrackOutput") }
+func (cb CommandBlock) SetTrackOutput(b bool) { cb.setFlag("TrackOutput", b) }
+
+// Powered checks, if the command block is powered by redstone.
+func (cb CommandBlock) Powered() bool     { return cb.getFlag("powered") }
+func (cb CommandBlock) SetPowered(b bool) { cb.setFlag("powered", b) }
+
+// Auto checks, if the command block is always active (Minecraft 1.9+).
+func (cb CommandBlock) Auto() bool     { return cb.getFlag("auto") }
+func (cb CommandBlock) SetAuto(b bool) { cb.setFlag("auto", b) }
+
+// NoteBlock is a view of a note block. Since Minecraft 1.13 note blocks have no tile entity, the note is a block state property.
+type NoteBlock struct{ tileEntity }
+
+// AsNoteBlock returns the note block view of a tile entity.
+func AsNoteBlock(te nbt.TagCompound) NoteBlock { return NoteBlock{tileEntity(te)} }
+
+// NewNoteBlock creates a note block with the given note (0 - 24). Returns nil since Minecraft 1.13.
+func NewNoteBlock(note byte, dataVersion int32) nbt.TagCompound {
+	if dataVersion >= dataVersionFlattening {
+		return nil
+	}
+	nb := NoteBlock{newTileEntity("noteblock", dataVersion)}
+	nb.SetNote(note)
+	nb.setFlag("powered", false)
+	return nb.NBT()
+}
+
+// Note returns the pitch in semitones (0 - 24).
+func (nb NoteBlock) Note() byte        { return nb.getByte("note") }
+func (nb NoteBlock) SetNote(note byte) { nb.setByte("note", note%25) }
+func (nb NoteBlock) Powered() bool     { return nb.getFlag("powered") }
+func (nb NoteBlock) SetPowered(b bool) { nb.setFlag("powered", b) }
+
+// Beacon is a view of a beacon.
+type Beacon struct{ tileEntity }
+
+// AsBeacon returns the beacon view of a tile entity.
+func AsBeacon(te nbt.TagCompound) Beacon { return Beacon{tileEntity(te)} }
+
+// NewBeacon creates a beacon without effects.
+func NewBeacon(dataVersion int32) nbt.TagCompound {
+	b := Beacon{newTileEntity("beacon", dataVersion)}
+	b.setInt("Levels", 0)
+	b.SetEffects(0, 0)
+	return b.NBT()
+}
+
+// Levels returns the number of layers of the pyramid below the beacon, as calculated by Minecraft.
+func (b Beacon) Levels() int32 { return b.getInt("Levels") }
+
+// Effects returns the numeric IDs of the primary and secondary effect (0 for none).
+func (b Beacon) Effects() (primary, secondary int32) {
+	return b.getInt("Primary"), b.getInt("Secondary")
+}
+
+func (b Beacon) SetEffects(primary, secondary int32) {
+	b.setInt("Primary", primary)
+	b.setInt("Secondary", secondary)
+}
+
+// Lock returns the name an item must have to open the beacon. Empty, if the beacon is not locked.
+func (b Beacon) Lock() string { return b.getString("Lock") }
+
+// Skull is a view of a mob head.
+type Skull struct{ tileEntity }
+
+// AsSkull returns the skull view of a tile entity.
+func AsSkull(te nbt.TagCompound) Skull { return Skull{tileEntity(te)} }
+
+// NewSkull creates a mob head. Before Minecraft 1.13, the type (0 = skeleton, 1 = wither skeleton, 2 = zombie, 3 = player,
+// 4 = creeper, 5 = dragon) and the rotation of heads on the floor (0 - 15) are stored in the tile entity,
+// since 1.13 they are part of the block state and the parameters are ignored.
+func NewSkull(skullType, rotation byte, dataVersion int32) nbt.TagCompound {
+	s := Skull{newTileEntity("skull", dataVersion)}
+	if dataVersion < dataVersionFlattening {
+		s.SetSkullType(skullType)
+		s.SetRotation(rotation)
+	}
+	return s.NBT()
+}
+
+// SkullType returns the type of the head (see NewSkull). Before Minecraft 1.13 only.
+func (s Skull) SkullType() byte      { return s.getByte("SkullType") }
+func (s Skull) SetSkullType(t byte)  { s.setByte("SkullType", t) }
+func (s Skull) Rotation() byte       { return s.getByte("Rot") }
+func (s Skull) SetRotation(rot byte) { s.setByte("Rot", rot&0xf) }
+
+// Tags of the owner of player heads in the different versions and the tag with the name in the owner compound.
+var skullOwnerTags = [][2]string{{"Owner", "Name"}, {"SkullOwner", "Name"}, {"profile", "name"}}
+
+// OwnerName returns the name of the player of a player head. Empty, if not set.
+func (s Skull) OwnerName() string {
+	for _, tags := range skullOwnerTags {
+		if owner, err := nbt.TagCompound(s.tileEntity).GetCompound(tags[0]); err == nil {
+			name, _ := owner.GetString(tags[1])
+			return name
+		}
+	}
+	return s.getString("ExtraType") // Before Minecraft 1.8.
+}
+
+// SetOwnerName sets the player of a player head. Minecraft looks up the UUID and skin of the player.
+func (s Skull) SetOwnerName(name string) {
+	for _, tags := range skullOwnerTags {
+		if _, err := nbt.TagCompound(s.tileEntity).GetCompound(tags[0]); err == nil {
+			s.setCompound(tags[0], nbt.TagCompound{tags[1]: nbt.Tag{nbt.TAG_String, name}})
+			return
+		}
+	}
+	s.setCompound("Owner", nbt.TagCompound{"Name": nbt.Tag{nbt.TAG_String, name}})
+}
+
+// BannerPattern is a pattern layer of a banner.
+type BannerPattern struct {
+	Pattern string // Pattern code, e.g. "bo" for a border.
+	Color   int32  // Dye color as stored (dye damage value before Minecraft 1.13, see Color since).
+}
+
+// Banner is a view of a banner.
+type Banner struct{ tileEntity }
+
+// AsBanner returns the banner view of a tile entity.
+func AsBanner(te nbt.TagCompound) Banner { return Banner{tileEntity(te)} }
+
+// NewBanner creates a banner without patterns. Before Minecraft 1.13, the base color is stored in the tile entity,
+// since 1.13 it is part of the block name and base is ignored.
+func NewBanner(base Color, dataVersion int32) nbt.TagCompound {
+	b := Banner{newTileEntity("banner", dataVersion)}
+	if dataVersion < dataVersionFlattening {
+		b.setInt("Base", int32(ColorBlack-base)) // Dye damage value
+	}
+	b.SetPatterns(nil)
+	return b.NBT()
+}
+
+// BaseColor returns the base color of banners from versions before Minecraft 1.13.
+func (b Banner) BaseColor() Color { return ColorBlack - Color(b.getInt("Base")) }
+
+func (b Banner) Patterns() []BannerPattern {
+	var patterns []BannerPattern
+	for _, tc := range b.getCompoundList("Patterns") {
+		pattern, _ := tc.GetString("Pattern")
+		color, _ := tc.GetInt("Color")
+		patterns = append(patterns, BannerPattern{pattern, color})
+	}
+	return patterns
+}
+
+func (b Banner) SetPatterns(patterns []BannerPattern) {
+	tcs := make([]nbt.TagCompound, len(patterns))
+	for i, p := range patterns {
+		tcs[i] = nbt.TagCompound{
+			"Pattern": nbt.Tag{nbt.TAG_String, p.Pattern},
+			"Color":   nbt.NewIntTag(p.Color),
+		}
+	}
+	b.setCompoundList("Patterns", tcs)
+}
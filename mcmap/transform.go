@@ -0,0 +1,297 @@
+package mcmap
+
+import (
+	"github.com/silvasur/gonbt/nbt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Rotation is a rotation around the Y axis (as used by structure blocks).
+type Rotation byte
+
+// Valid values for Rotation
+const (
+	RotationNone Rotation = iota
+	RotationClockwise90
+	RotationClockwise180
+	RotationCounterclockwise90
+)
+
+// Mirror is a mirroring in the horizontal plane (as used by structure blocks).
+type Mirror byte
+
+// Valid values for Mirror
+const (
+	MirrorNone      Mirror = iota
+	MirrorLeftRight        // Mirrors the Z axis, north and south are swapped.
+	MirrorFrontBack        // Mirrors the X axis, east and west are swapped.
+)
+
+// Horizontal directions in clockwise order.
+var facingsClockwise = []Facing{FacingNorth, FacingEast, FacingSouth, FacingWest}
+
+func (f Facing) rotated(rot Rotation) Facing {
+	for i, cf := range facingsClockwise {
+		if cf == f {
+			return facingsClockwise[(i+int(rot))%4]
+		}
+	}
+	return f
+}
+
+func (f Facing) mirrored(mirror Mirror) Facing {
+	if (mirror == MirrorLeftRight && (f == FacingNorth || f == FacingSouth)) || (mirror == MirrorFrontBack && (f == FacingWest || f == FacingEast)) {
+		return f.Opposite()
+	}
+	return f
+}
+
+// transformed mirrors and then rotates the direction.
+func (f Facing) transformed(rot Rotation, mirror Mirror) Facing {
+	return f.mirrored(mirror).rotated(rot)
+}
+
+func parseFacing(s string) (Facing, bool) {
+	for f, name := range facingNames {
+		if name == s {
+			return f, true
+		}
+	}
+	return facingInvalid, false
+}
+
+// transformRotation16 transforms the rotation of signs, banners and skulls (16 steps, 0 is south, clockwise).
+func transformRotation16(r int, rot Rotation, mirror Mirror) int {
+	switch mirror {
+	case MirrorLeftRight:
+		r = 24 - r
+	case MirrorFrontBack:
+		r = 16 - r
+	}
+	return (r + 4*int(rot)) % 16
+}
+
+// transformYaw transforms the yaw of an entity (in degrees, 0 is south, clockwise).
+func transformYaw(yaw float32, rot Rotation, mirror Mirror) float32 {
+	switch mirror {
+	case MirrorLeftRight:
+		yaw = 180 - yaw
+	case MirrorFrontBack:
+		yaw = -yaw
+	}
+	yaw = float32(math.Mod(float64(yaw)+90*float64(rot), 360))
+	if yaw < 0 {
+		yaw += 360
+	}
+	return yaw
+}
+
+// transformXZ transforms the horizontal position x, z inside an area of the size w, l (the area starts at 0, 0).
+// The transformed area starts at 0, 0 too.
+func transformXZ(x, z, w, l float64, rot Rotation, mirror Mirror) (float64, float64) {
+	switch mirror {
+	case MirrorLeftRight:
+		z = l - z
+	case MirrorFrontBack:
+		x = w - x
+	}
+
+	switch rot {
+	case RotationClockwise90:
+		return l - z, x
+	case RotationClockwise180:
+		return w - x, l - z
+	case RotationCounterclockwise90:
+		return z, w - x
+	}
+	return x, z
+}
+
+// transformDirectionName transforms the name of a horizontal direction, other strings are returned unchanged.
+func transformDirectionName(s string, rot Rotation, mirror Mirror) string {
+	f, ok := parseFacing(s)
+	if !ok {
+		return s
+	}
+	return f.transformed(rot, mirror).String()
+}
+
+// Canonical names of rail shapes connecting two directions.
+var railShapeNames = map[[2]Facing]string{
+	{FacingNorth, FacingSouth}: "north_south",
+	{FacingEast, FacingWest}:   "east_west",
+	{FacingSouth, FacingEast}:  "south_east",
+	{FacingSouth, FacingWest}:  "south_west",
+	{FacingNorth, FacingWest}:  "north_west",
+	{FacingNorth, FacingEast}:  "north_east",
+}
+
+func transformShape(shape string, rot Rotation, mirror Mirror) string {
+	// Stairs
+	if mirror != MirrorNone {
+		switch {
+		case strings.HasSuffix(shape, "_left"):
+			return strings.TrimSuffix(shape, "_left") + "_right"
+		case strings.HasSuffix(shape, "_right"):
+			return strings.TrimSuffix(shape, "_right") + "_left"
+		}
+	}
+
+	// Rails
+	parts := strings.SplitN(shape, "_", 2)
+	if len(parts) != 2 {
+		return shape
+	}
+	if parts[0] == "ascending" {
+		return "ascending_" + transformDirectionName(parts[1], rot, mirror)
+	}
+	a, okA := parseFacing(parts[0])
+	b, okB := parseFacing(parts[1])
+	if !okA || !okB {
+		return shape
+	}
+	a, b = a.transformed(rot, mirror), b.transformed(rot, mirror)
+	if name, ok := railShapeNames[[2]Facing{a, b}]; ok {
+		return name
+	}
+	if name, ok := railShapeNames[[2]Facing{b, a}]; ok {
+		return name
+	}
+	return shape
+}
+
+// Transformed returns the block state mirrored and then rotated around the Y axis.
+// Directions, axes, rotations, connections to neighbours, rail and stair shapes, door hinges and chest halves are transformed.
+func (bs *BlockState) Transformed(rot Rotation, mirror Mirror) *BlockState {
+	if len(bs.Properties) == 0 || (rot == RotationNone && mirror == MirrorNone) {
+		return bs
+	}
+
+	props := make(map[string]string, len(bs.Properties))
+	for k, v := range bs.Properties {
+		switch k {
+		case "facing":
+			v = transformDirectionName(v, rot, mirror)
+		case "axis":
+			if rot == RotationClockwise90 || rot == RotationCounterclockwise90 {
+				switch v {
+				case "x":
+					v = "z"
+				case "z":
+					v = "x"
+				}
+			}
+		case "rotation":
+			if r, err := strconv.Atoi(v); err == nil {
+				v = strconv.Itoa(transformRotation16(r, rot, mirror))
+			}
+		case "shape":
+			v = transformShape(v, rot, mirror)
+		case "hinge", "type":
+			if mirror != MirrorNone {
+				switch v {
+				case "left":
+					v = "right"
+				case "right":
+					v = "left"
+				}
+			}
+		case "orientation":
+			parts := strings.Split(v, "_")
+			for i, part := range parts {
+				parts[i] = transformDirectionName(part, rot, mirror)
+			}
+			v = strings.Join(parts, "_")
+		case "north", "east", "south", "west":
+			k = transformDirectionName(k, rot, mirror)
+		}
+		props[k] = v
+	}
+
+	return &BlockState{Name: bs.Name, Properties: props}
+}
+
+// Transformed returns a copy of the schematic, mirrored and then rotated around the Y axis.
+// The origin of the copy is the lowest corner of the transformed cuboid.
+// Blocks (see BlockState.Transformed), entities (position, yaw and facing of hanging entities) and the rotation of skulls
+// before Minecraft 1.13 are transformed.
+func (s *Schematic) Transformed(rot Rotation, mirror Mirror) *Schematic {
+	w, l := s.Width, s.Length
+	if rot == RotationClockwise90 || rot == RotationCounterclockwise90 {
+		w, l = l, w
+	}
+
+	t := NewSchematic(w, s.Height, l)
+	t.DataVersion = s.DataVersion
+	t.extra, t.extraFormat = copyCompound(s.extra), s.extraFormat
+
+	// Caches the transformed blocks
+	transformed := make(map[blockKey]Block)
+
+	for off, blk := range s.blocks {
+		x, y, z := off%s.Width, off/(s.Width*s.Length), (off/s.Width)%s.Length
+		tx, tz := transformBlockXZ(x, z, s.Width, s.Length, rot, mirror)
+
+		blk = copyBlock(blk)
+		key := stateKey(blk)
+		tb, ok := transformed[key]
+		if !ok {
+			tb = blk
+			bs := blk.BlockState()
+			if ts := bs.Transformed(rot, mirror); ts != bs {
+				tb.SetState(ts)
+			}
+			transformed[key] = tb
+		}
+		blk.ID, blk.Data, blk.State = tb.ID, tb.Data, tb.State
+		if r, err := blk.TileEntity.GetByte("Rot"); err == nil {
+			blk.TileEntity["Rot"] = nbt.NewByteTag(byte(transformRotation16(int(r&0xf), rot, mirror)))
+		}
+
+		*t.Block(tx, y, tz) = blk
+	}
+
+	for _, ent := range s.Entities {
+		t.Entities = append(t.Entities, ent.transformed(s.Width, s.Length, s.DataVersion, rot, mirror))
+	}
+
+	return t
+}
+
+func transformBlockXZ(x, z, w, l int, rot Rotation, mirror Mirror) (int, int) {
+	tx, tz := transformXZ(float64(x)+0.5, float64(z)+0.5, float64(w), float64(l), rot, mirror)
+	return int(math.Floor(tx)), int(math.Floor(tz))
+}
+
+// transformed returns a copy of the entity, transformed inside of a schematic of the size w, l.
+func (e *Entity) transformed(w, l int, dataVersion int32, rot Rotation, mirror Mirror) *Entity {
+	cp := e.translated(0, 0, 0)
+	cp.Pos[0], cp.Pos[2] = transformXZ(e.Pos[0], e.Pos[2], float64(w), float64(l), rot, mirror)
+	cp.Rotation[0] = transformYaw(e.Rotation[0], rot, mirror)
+
+	// Hanging entities
+	tileX, errX := cp.NBT.GetInt("TileX")
+	tileZ, errZ := cp.NBT.GetInt("TileZ")
+	if errX == nil && errZ == nil {
+		tx, tz := transformBlockXZ(int(tileX), int(tileZ), w, l, rot, mirror)
+		cp.NBT["TileX"] = nbt.NewIntTag(int32(tx))
+		cp.NBT["TileZ"] = nbt.NewIntTag(int32(tz))
+	}
+	if facing, err := cp.NBT.GetByte("Facing"); err == nil {
+		// Since Minecraft 1.13 the facing is stored like Facing, before only horizontal directions were possible.
+		if dataVersion >= dataVersionFlattening {
+			facing = byte(Facing(facing).transformed(rot, mirror))
+		} else if int(facing) < len(facingsHorizontal) {
+			f := facingsHorizontal[facing].transformed(rot, mirror)
+			for i, hf := range facingsHorizontal {
+				if hf == f {
+					facing = byte(i)
+				}
+			}
+		}
+		cp.NBT["Facing"] = nbt.NewByteTag(facing)
+	}
+
+	return cp
+}
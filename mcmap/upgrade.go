@@ -0,0 +1,367 @@
+package mcmap
+
+import (
+	"github.com/silvasur/gonbt/nbt"
+	"strings"
+)
+
+// upgradeDataVersion is the data version of chunks converted by UpgradeChunk (Minecraft 1.13.2).
+// The game upgrades these chunks further, if needed.
+const upgradeDataVersion = 1631
+
+// Tags of the Level compound that are no longer used in the 1.13 format.
+var legacyOnlyLevelTags = []string{"LightPopulated", "V"}
+
+// Tile entity IDs used before Minecraft 1.11 and their namespaced equivalents.
+var oldTileEntityIDs = map[string]string{
+	"Airportal":    "end_portal",
+	"Banner":       "banner",
+	"Beacon":       "beacon",
+	"Bed":          "bed",
+	"Cauldron":     "brewing_stand",
+	"Chest":        "chest",
+	"Comparator":   "comparator",
+	"Control":      "command_block",
+	"DLDetector":   "daylight_detector",
+	"Dropper":      "dropper",
+	"EnchantTable": "enchanting_table",
+	"EndGateway":   "end_gateway",
+	"EnderChest":   "ender_chest",
+	"FlowerPot":    "flower_pot",
+	"Furnace":      "furnace",
+	"Hopper":       "hopper",
+	"MobSpawner":   "mob_spawner",
+	"Music":        "noteblock",
+	"Piston":       "piston",
+	"RecordPlayer": "jukebox",
+	"Sign":         "sign",
+	"Skull":        "skull",
+	"Structure":    "structure_block",
+	"Trap":         "dispenser",
+}
+
+// Fluid names of scheduled fluid updates, that were block updates before Minecraft 1.13.
+var legacyFluidTicks = map[BlockID]string{
+	BlkWater:           "minecraft:flowing_water",
+	BlkStationaryWater: "minecraft:water",
+	BlkLava:            "minecraft:flowing_lava",
+	BlkStationaryLava:  "minecraft:lava",
+}
+
+// Blocks whose state depends on their neighbours (connections, shapes).
+// UpgradeChunk marks them for post processing, so the game recalculates their state when loading the chunk.
+var neighbourDependentBlocks = map[BlockID]bool{
+	BlkRedstoneWire:     true,
+	BlkChest:            true,
+	BlkTrappedChest:     true,
+	BlkFence:            true,
+	BlkSpruceFence:      true,
+	BlkBirchFence:       true,
+	BlkJungleFence:      true,
+	BlkDarkOakFence:     true,
+	BlkAcaciaFence:      true,
+	BlkNetherBrickFence: true,
+	BlkIronBars:         true,
+	BlkGlassPane:        true,
+	BlkStainedGlassPane: true,
+	BlkCobblestoneWall:  true,
+	BlkTripwire:         true,
+	BlkChorusPlant:      true,
+	BlkVines:            true,
+	BlkFenceGate:        true,
+	BlkSpruceFenceGate:  true,
+	BlkBirchFenceGate:   true,
+	BlkJungleFenceGate:  true,
+	BlkDarkOakFenceGate: true,
+	BlkAcaciaFenceGate:  true,
+	BlkPumpkinStem:      true,
+	BlkMelonStem:        true,
+}
+
+func init() {
+	for _, id := range stairIDs {
+		neighbourDependentBlocks[id] = true
+	}
+}
+
+// renameState returns a copy of bs with another name (without namespace).
+func renameState(bs *BlockState, name string) *BlockState {
+	return &BlockState{Name: "minecraft:" + name, Properties: bs.Properties}
+}
+
+// mergeProperties returns a copy of bs with the properties names copied from other.
+func mergeProperties(bs, other *BlockState, names ...string) *BlockState {
+	for _, name := range names {
+		if v, ok := other.Properties[name]; ok {
+			bs = bs.With(name, v)
+		}
+	}
+	return bs
+}
+
+func upgradeTileEntityID(id string, blkID BlockID) string {
+	if newID, ok := oldTileEntityIDs[id]; ok {
+		id = newID
+	}
+	id = NormalizeBlockName(id)
+
+	if id == "minecraft:chest" && blkID == BlkTrappedChest {
+		return "minecraft:trapped_chest"
+	}
+	return id
+}
+
+// pottedPlant returns the name of the potted plant block of a flower pot tile entity.
+func pottedPlant(tileEntity nbt.TagCompound, blocks *BlockRegistry) string {
+	var id BlockID
+	switch item := tileEntity["Item"]; item.Type {
+	case nbt.TAG_String:
+		var ok bool
+		if id, ok = blocks.ID(item.Payload.(string)); !ok {
+			return "flower_pot"
+		}
+	case nbt.TAG_Int:
+		id = BlockID(item.Payload.(int32))
+	default:
+		return "flower_pot"
+	}
+
+	_data, _ := tileEntity.GetInt("Data")
+	data := byte(_data)
+
+	switch id {
+	case BlkSaplings:
+		if wood := WoodType(data & 7); wood <= WoodDarkOak {
+			return "potted_" + wood.String() + "_sapling"
+		}
+	case BlkDandelion:
+		return "potted_dandelion"
+	case BlkFlower:
+		if name := pick(flowerNames, data); name != "" {
+			return "potted_" + name
+		}
+	case BlkRedMushroom:
+		return "potted_red_mushroom"
+	case BlkBrownMushroom:
+		return "potted_brown_mushroom"
+	case BlkDeadBush:
+		return "potted_dead_bush"
+	case BlkCactus:
+		return "potted_cactus"
+	case BlkGrass:
+		if data == 2 {
+			return "potted_fern"
+		}
+	}
+	return "flower_pot"
+}
+
+// noteBlockInstrument returns the instrument of a note block placed on the given block.
+func noteBlockInstrument(below BlockID) string {
+	switch below {
+	case BlkWoodPlanks, BlkWood, BlkWood2, BlkWoodenSlab, BlkWoodenDoubleSlab, BlkBookshelf, BlkChest, BlkTrappedChest,
+		BlkCraftingTable, BlkNoteBlock, BlkJukebox, BlkFence, BlkHugeBrownMushroom, BlkHugeRedMushroom:
+		return "bass"
+	case BlkSand, BlkGravel, BlkSoulSand, BlkConcretePowder:
+		return "snare"
+	case BlkGlass, BlkStainedGlass, BlkGlassPane, BlkStainedGlassPane, BlkGlowstone, BlkSeaLantern:
+		return "hat"
+	case BlkStone, BlkCobblestone, BlkBedrock, BlkSandstone, BlkRedSandstone, BlkBricks, BlkMossStone, BlkObsidian,
+		BlkStoneBricks, BlkNetherBrick, BlkNetherrack, BlkEndStone, BlkNetherQuartzOre, BlkBlockOfQuartz, BlkDoubleSlabs,
+		BlkSlabs, BlkGoldOre, BlkIronOre, BlkCoalOre, BlkDiamondOre, BlkEmeraldOre, BlkLapisLazuliOre, BlkRedstoneOre,
+		BlkPrismarine, BlkStainedClay, BlkHardenedClay, BlkConcrete, BlkPurpurBlock, BlkMagmaBlock:
+		return "basedrum"
+	case BlkBlockOfGold:
+		return "bell"
+	case BlkClay:
+		return "flute"
+	case BlkPackedIce:
+		return "chime"
+	case BlkWool:
+		return "guitar"
+	case BlkBoneBlock:
+		return "xylophone"
+	}
+	return "harp"
+}
+
+// UpgradeChunk converts a chunk using numeric block IDs and data values (Minecraft 1.12 and earlier)
+// to the block state format of Minecraft 1.13. Chunks already using block states are not changed.
+//
+// Block states that depend on the tile entity (beds, banners, skulls, flower pots, note blocks) or on the
+// neighbouring blocks (doors, double plants, snowy grass) are converted. Tile entity IDs get renamed.
+// Entity IDs (including the types split in Minecraft 1.11), item stacks (numeric IDs and damage values
+// that were part of the item type) and names of entities, tile entities and items are converted too.
+// Blocks connecting to their neighbours (fences, stairs, redstone wire, ...) are marked, so the game
+// recalculates their state when loading the chunk.
+func UpgradeChunk(c *Chunk) {
+	if c.dataVersion >= dataVersionFlattening {
+		return
+	}
+
+	blocks := DefaultBlocks
+	if c.reg != nil {
+		blocks = c.reg.Blocks()
+	}
+
+	// The states of the unmodified blocks. Needed to look at the neighbours.
+	stateCache := make(map[uint32]*BlockState)
+	states := make([]*BlockState, len(c.blocks))
+	for off, blk := range c.blocks {
+		key := uint32(blk.ID)<<4 | uint32(blk.Data&0xf)
+		bs, ok := stateCache[key]
+		if !ok {
+			blk.State = nil
+			bs = blk.BlockState()
+			stateCache[key] = bs
+		}
+		states[off] = bs
+	}
+	stateAt := func(x, y, z int) *BlockState {
+		if off := c.blockOffset(x, y, z); off >= 0 {
+			return states[off]
+		}
+		return nil
+	}
+
+	postProcessing := make([][]int16, c.sizeY/16)
+
+	c.Iter(func(x, y, z int, blk *Block) {
+		bs := stateAt(x, y, z)
+		te := blk.TileEntity
+
+		switch {
+		case blk.ID == BlkBed:
+			if col, err := te.GetInt("color"); err == nil && Color(col) <= ColorBlack {
+				bs = renameState(bs, Color(col).String()+"_bed")
+			}
+		case blk.ID == BlkStandingBanner || blk.ID == BlkWallBanner:
+			if base, err := te.GetInt("Base"); err == nil && base >= 0 && base <= 15 {
+				col := Color(15 - base) // Base is a dye damage value
+				bs = renameState(bs, col.String()+strings.TrimPrefix(bs.Name, "minecraft:white"))
+				delete(te, "Base")
+			}
+		case blk.ID == BlkMobHead:
+			typ, err := te.GetByte("SkullType")
+			if err != nil || int(typ) >= len(skullNames) {
+				typ = 0
+			}
+			if blk.Data&7 == 1 {
+				rot, _ := te.GetByte("Rot")
+				bs = renameState(bs, skullNames[typ]).With("rotation", intProp(int(rot&0xf)))
+			} else {
+				bs = renameState(bs, wallSkullNames[typ])
+			}
+			delete(te, "SkullType")
+			delete(te, "Rot")
+		case blk.ID == BlkFlowerPot:
+			if te != nil {
+				bs = st(pottedPlant(te, blocks))
+			}
+			blk.TileEntity = nil // Flower pots have no tile entity since 1.13.
+		case blk.ID == BlkNoteBlock:
+			note, _ := te.GetByte("note")
+			powered, _ := te.GetByte("powered")
+			instrument := "harp"
+			if below := stateAt(x, y-1, z); below != nil {
+				id, _ := below.Legacy()
+				instrument = noteBlockInstrument(id)
+			}
+			bs = st("note_block", "instrument", instrument, "note", intProp(int(note%25)), "powered", boolProp(powered != 0))
+			blk.TileEntity = nil // Note blocks have no tile entity since 1.13.
+		case blk.ID == BlkLargeFlower && bs.Property("half") == "upper":
+			if below := stateAt(x, y-1, z); below != nil && below.Property("half") == "lower" {
+				bs = &BlockState{Name: below.Name, Properties: bs.Properties}
+			}
+		case isOneOf(blk.ID, doorIDs):
+			if bs.Property("half") == "upper" {
+				if below := stateAt(x, y-1, z); below != nil && below.Name == bs.Name {
+					bs = mergeProperties(bs, below, "facing", "open")
+				}
+			} else if above := stateAt(x, y+1, z); above != nil && above.Name == bs.Name {
+				bs = mergeProperties(bs, above, "hinge", "powered")
+			}
+		case bs.Property("snowy") != "":
+			if above := stateAt(x, y+1, z); above != nil && (above.Name == "minecraft:snow" || above.Name == "minecraft:snow_block") {
+				bs = bs.With("snowy", "true")
+			}
+		}
+
+		if blk.TileEntity != nil {
+			if id, err := blk.TileEntity.GetString("id"); err == nil {
+				blk.TileEntity["id"] = nbt.Tag{nbt.TAG_String, upgradeTileEntityID(id, blk.ID)}
+			}
+			upgradeTileEntityNBT(blk.TileEntity)
+		}
+
+		if neighbourDependentBlocks[blk.ID] {
+			section := (y - c.minY) >> 4
+			postProcessing[section] = append(postProcessing[section], int16(x|(y&0xf)<<4|z<<8))
+		}
+
+		if tick := blk.Tick; tick != nil {
+			if fluid, ok := legacyFluidTicks[BlockID(tick.i)]; ok {
+				tick.name = fluid
+				if c.fluidTicks == nil {
+					c.fluidTicks = make(map[int]*TileTick)
+				}
+				c.fluidTicks[c.blockOffset(x, y, z)] = tick
+				blk.Tick = nil
+			} else if BlockID(tick.i) == blk.ID {
+				tick.name = bs.Name
+			} else {
+				tick.name = "" // Derived from the ID when saving.
+			}
+		}
+
+		blk.SetState(bs)
+	})
+
+	for _, ent := range c.Entities {
+		ent.upgrade()
+	}
+
+	postProcessingList := make([]nbt.TagList, len(postProcessing))
+	for i, positions := range postProcessing {
+		postProcessingList[i] = nbt.NewListTag(nbt.TAG_Short, positions).Payload.(nbt.TagList)
+	}
+
+	c.dataVersion = upgradeDataVersion
+	if c.populated {
+		c.status = "postprocessed"
+	} else {
+		c.status = "liquid_carved"
+	}
+	extra := c.ExtraTags()
+	for _, name := range legacyOnlyLevelTags {
+		delete(extra, name)
+	}
+	extra["PostProcessing"] = nbt.NewListTag(nbt.TAG_List, postProcessingList)
+	c.MarkModified()
+}
+
+// UpgradeChunks converts all chunks of the region with UpgradeChunk and sets the format of new chunks accordingly (see SetChunkFormat).
+// The region gets saved.
+func (reg *Region) UpgradeChunks() error {
+	for pos := range reg.AllChunks() {
+		chunk, err := reg.Chunk(pos.X, pos.Z)
+		switch err {
+		case nil:
+		case NotAvailable:
+			continue
+		default:
+			return err
+		}
+
+		UpgradeChunk(chunk)
+
+		if err := chunk.MarkUnused(); err != nil {
+			return err
+		}
+	}
+
+	if reg.dataVersion < upgradeDataVersion {
+		reg.SetChunkFormat(upgradeDataVersion, 0, ChunkSizeY)
+	}
+	return reg.Save()
+}
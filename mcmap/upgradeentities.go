@@ -0,0 +1,605 @@
+package mcmap
+
+import (
+	"encoding/json"
+	"github.com/silvasur/gonbt/nbt"
+	"strings"
+)
+
+// This file converts entities and items to the format of Minecraft 1.13 (see UpgradeChunk).
+
+// Entity IDs used before Minecraft 1.11 and their equivalents in 1.11.
+var oldEntityIDs = map[string]string{
+	"AreaEffectCloud":       "area_effect_cloud",
+	"ArmorStand":            "armor_stand",
+	"Arrow":                 "arrow",
+	"Bat":                   "bat",
+	"Blaze":                 "blaze",
+	"Boat":                  "boat",
+	"CaveSpider":            "cave_spider",
+	"Chicken":               "chicken",
+	"Cow":                   "cow",
+	"Creeper":               "creeper",
+	"DragonFireball":        "dragon_fireball",
+	"EnderCrystal":          "ender_crystal",
+	"EnderDragon":           "ender_dragon",
+	"Enderman":              "enderman",
+	"Endermite":             "endermite",
+	"EntityHorse":           "horse",
+	"EyeOfEnderSignal":      "eye_of_ender_signal",
+	"FallingSand":           "falling_block",
+	"Fireball":              "fireball",
+	"FireworksRocketEntity": "fireworks_rocket",
+	"Ghast":                 "ghast",
+	"Giant":                 "giant",
+	"Guardian":              "guardian",
+	"Item":                  "item",
+	"ItemFrame":             "item_frame",
+	"LavaSlime":             "magma_cube",
+	"LeashKnot":             "leash_knot",
+	"LightningBolt":         "lightning_bolt",
+	"MinecartChest":         "chest_minecart",
+	"MinecartCommandBlock":  "commandblock_minecart",
+	"MinecartFurnace":       "furnace_minecart",
+	"MinecartHopper":        "hopper_minecart",
+	"MinecartRideable":      "minecart",
+	"MinecartSpawner":       "spawner_minecart",
+	"MinecartTNT":           "tnt_minecart",
+	"MushroomCow":           "mooshroom",
+	"Ozelot":                "ocelot",
+	"Painting":              "painting",
+	"Pig":                   "pig",
+	"PigZombie":             "zombie_pigman",
+	"PolarBear":             "polar_bear",
+	"PrimedTnt":             "tnt",
+	"Rabbit":                "rabbit",
+	"Sheep":                 "sheep",
+	"Shulker":               "shulker",
+	"ShulkerBullet":         "shulker_bullet",
+	"Silverfish":            "silverfish",
+	"Skeleton":              "skeleton",
+	"Slime":                 "slime",
+	"SmallFireball":         "small_fireball",
+	"SnowMan":               "snowman",
+	"Snowball":              "snowball",
+	"SpectralArrow":         "spectral_arrow",
+	"Spider":                "spider",
+	"Squid":                 "squid",
+	"ThrownEgg":             "egg",
+	"ThrownEnderpearl":      "ender_pearl",
+	"ThrownExpBottle":       "xp_bottle",
+	"ThrownPotion":          "potion",
+	"TippedArrow":           "arrow",
+	"Villager":              "villager",
+	"VillagerGolem":         "villager_golem",
+	"Witch":                 "witch",
+	"WitherBoss":            "wither",
+	"WitherSkull":           "wither_skull",
+	"Wolf":                  "wolf",
+	"XPOrb":                 "xp_orb",
+	"Zombie":                "zombie",
+}
+
+// Entity IDs of Minecraft 1.11 and 1.12 that were renamed in 1.13.
+var renamedEntityIDs = map[string]string{
+	"commandblock_minecart": "command_block_minecart",
+	"ender_crystal":         "end_crystal",
+	"evocation_fangs":       "evoker_fangs",
+	"evocation_illager":     "evoker",
+	"eye_of_ender_signal":   "eye_of_ender",
+	"fireworks_rocket":      "firework_rocket",
+	"illusion_illager":      "illusioner",
+	"snowman":               "snow_golem",
+	"villager_golem":        "iron_golem",
+	"vindication_illager":   "vindicator",
+	"xp_bottle":             "experience_bottle",
+	"xp_orb":                "experience_orb",
+}
+
+// Numeric entity IDs, used as the damage value of spawn eggs before Minecraft 1.9.
+var numericEntityIDs = map[int]string{
+	50: "Creeper", 51: "Skeleton", 52: "Spider", 54: "Zombie", 55: "Slime", 56: "Ghast", 57: "PigZombie", 58: "Enderman",
+	59: "CaveSpider", 60: "Silverfish", 61: "Blaze", 62: "LavaSlime", 65: "Bat", 66: "Witch", 67: "Endermite", 68: "Guardian",
+	69: "Shulker", 90: "Pig", 91: "Sheep", 92: "Cow", 93: "Chicken", 94: "Squid", 95: "Wolf", 96: "MushroomCow", 98: "Ozelot",
+	100: "EntityHorse", 101: "Rabbit", 102: "PolarBear", 120: "Villager",
+}
+
+var (
+	horseNames    = []string{"horse", "donkey", "mule", "zombie_horse", "skeleton_horse"}
+	skeletonNames = []string{"skeleton", "wither_skeleton", "stray"}
+)
+
+// Entity IDs of Minecraft 1.11 (without namespace) and their equivalents before 1.11 or 1.13, see entityID.
+var (
+	legacyEntityIDs        = make(map[string]string)
+	preFlatteningEntityIDs = make(map[string]string)
+)
+
+func init() {
+	for old, id := range oldEntityIDs {
+		if old != "TippedArrow" {
+			legacyEntityIDs[id] = old
+		}
+	}
+	for old, id := range renamedEntityIDs {
+		preFlatteningEntityIDs[id] = old
+	}
+}
+
+// namespacedEntityID returns the entity ID of Minecraft 1.11 for an entity ID of an older version.
+// The entity types that were split in Minecraft 1.11 (zombies, skeletons, horses, guardians) are distinguished
+// by the NBT data tc of the entity (may be nil), the tags used for that are removed.
+func namespacedEntityID(id string, tc nbt.TagCompound) string {
+	if newID, ok := oldEntityIDs[id]; ok {
+		id = newID
+		if tc != nil {
+			id = splitEntityID(id, tc)
+		}
+	}
+	return NormalizeBlockName(id)
+}
+
+// upgradeEntityID returns the entity ID of Minecraft 1.13 for an entity ID of an older version, see namespacedEntityID.
+func upgradeEntityID(id string, tc nbt.TagCompound) string {
+	id = namespacedEntityID(id, tc)
+	if newID, ok := renamedEntityIDs[strings.TrimPrefix(id, "minecraft:")]; ok && strings.HasPrefix(id, "minecraft:") {
+		id = "minecraft:" + newID
+	}
+	return id
+}
+
+// entityID returns the ID of an entity of Minecraft 1.13+ (e.g. "minecraft:snow_golem") in the format of the given data version.
+// The entity types split in Minecraft 1.11 keep their new IDs.
+func entityID(id string, dataVersion int32) string {
+	if !strings.HasPrefix(id, "minecraft:") {
+		return id
+	}
+	name := strings.TrimPrefix(id, "minecraft:")
+	if dataVersion < dataVersionFlattening {
+		if old, ok := preFlatteningEntityIDs[name]; ok {
+			name = old
+		}
+	}
+	if dataVersion < dataVersionNamespacedIDs {
+		if old, ok := legacyEntityIDs[name]; ok {
+			return old
+		}
+	}
+	return "minecraft:" + name
+}
+
+func splitEntityID(id string, tc nbt.TagCompound) string {
+	switch id {
+	case "zombie":
+		// Minecraft 1.10 uses ZombieType (1-5: villager profession + 1, 6: husk), older versions IsVillager.
+		if typ, err := tc.GetInt("ZombieType"); err == nil {
+			delete(tc, "ZombieType")
+			switch {
+			case typ >= 1 && typ <= 5:
+				tc["Profession"] = nbt.NewIntTag(typ - 1)
+				return "zombie_villager"
+			case typ == 6:
+				return "husk"
+			}
+		} else if v, err := tc.GetByte("IsVillager"); err == nil {
+			delete(tc, "IsVillager")
+			if v != 0 {
+				if prof, err := tc.GetInt("VillagerProfession"); err == nil {
+					tc["Profession"] = nbt.NewIntTag(prof)
+				}
+				return "zombie_villager"
+			}
+		}
+	case "skeleton":
+		if typ, err := tc.GetByte("SkeletonType"); err == nil {
+			delete(tc, "SkeletonType")
+			if name := pick(skeletonNames, typ); name != "" {
+				return name
+			}
+		}
+	case "horse":
+		if typ, err := tc.GetInt("Type"); err == nil {
+			delete(tc, "Type")
+			if typ >= 0 && int(typ) < len(horseNames) {
+				return horseNames[typ]
+			}
+		}
+	case "guardian":
+		if elder, err := tc.GetByte("Elder"); err == nil {
+			delete(tc, "Elder")
+			if elder != 0 {
+				return "elder_guardian"
+			}
+		}
+	}
+	return id
+}
+
+// jsonText converts a plain text to a JSON text component, as used for names since Minecraft 1.13.
+func jsonText(s string) string {
+	b, _ := json.Marshal(s)
+	return `{"text":` + string(b) + `}`
+}
+
+// upgrade converts the entity to the format of Minecraft 1.13, see upgradeEntityNBT.
+func (e *Entity) upgrade() {
+	if e.ID == "" {
+		return
+	}
+	e.ID = upgradeEntityID(e.ID, e.NBT)
+	if e.CustomName != "" {
+		e.CustomName = jsonText(e.CustomName)
+	}
+	upgradeEntityNBT(e.ID, e.NBT)
+}
+
+// upgradeEntityNBT converts the tags of an entity with the (already upgraded) ID id to the format of Minecraft 1.13:
+// The items (see upgradeItems), the equipment of Minecraft 1.8, falling and carried blocks, the custom name
+// and the passengers are converted.
+func upgradeEntityNBT(id string, tc nbt.TagCompound) {
+	if tc == nil {
+		return
+	}
+
+	if name, err := tc.GetString("CustomName"); err == nil && name != "" {
+		tc["CustomName"] = nbt.Tag{nbt.TAG_String, jsonText(name)}
+	}
+
+	// Equipment: hand, feet, legs, chest, head
+	if equipment, err := tc.GetList("Equipment"); err == nil && equipment.Type == nbt.TAG_Compound && len(equipment.Elems) == 5 {
+		tc["HandItems"] = nbt.NewListTag(nbt.TAG_Compound, []nbt.TagCompound{equipment.Elems[0].(nbt.TagCompound), {}})
+		armor := make([]nbt.TagCompound, 4)
+		for i := range armor {
+			armor[i] = equipment.Elems[i+1].(nbt.TagCompound)
+		}
+		tc["ArmorItems"] = nbt.NewListTag(nbt.TAG_Compound, armor)
+		delete(tc, "Equipment")
+	}
+
+	switch id {
+	case "minecraft:falling_block":
+		if blkID, ok := legacyBlockTag(tc, "TileID", "Block"); ok {
+			data, _ := tc.GetByte("Data")
+			if bs := legacyBlockState(blkID, data); bs != nil {
+				tc["BlockState"] = nbt.Tag{nbt.TAG_Compound, bs.toNBT()}
+			}
+			for _, name := range []string{"TileID", "Block", "Data"} {
+				delete(tc, name)
+			}
+		}
+	case "minecraft:enderman":
+		if blkID, ok := legacyBlockTag(tc, "carried", "carried"); ok {
+			data, _ := tc.GetShort("carriedData")
+			if bs := legacyBlockState(blkID, byte(data)); bs != nil && blkID != BlkAir {
+				tc["carriedBlockState"] = nbt.Tag{nbt.TAG_Compound, bs.toNBT()}
+			}
+			delete(tc, "carried")
+			delete(tc, "carriedData")
+		}
+	}
+
+	if passengers, err := tc.GetList("Passengers"); err == nil && passengers.Type == nbt.TAG_Compound {
+		for _, elem := range passengers.Elems {
+			upgradeEntityCompound(elem.(nbt.TagCompound))
+		}
+	}
+
+	upgradeItems(tc)
+}
+
+// upgradeEntityCompound converts the ID and the tags of an entity stored in another tag (passengers, spawners).
+func upgradeEntityCompound(tc nbt.TagCompound) {
+	id, err := tc.GetString("id")
+	if err != nil {
+		return
+	}
+	id = upgradeEntityID(id, tc)
+	tc["id"] = nbt.Tag{nbt.TAG_String, id}
+	upgradeEntityNBT(id, tc)
+}
+
+// legacyBlockTag reads a numeric block ID (tag numName, int or short) or a block name (tag strName).
+func legacyBlockTag(tc nbt.TagCompound, numName, strName string) (BlockID, bool) {
+	if name, err := tc.GetString(strName); err == nil {
+		return DefaultBlocks.ID(name)
+	}
+	switch tag := tc[numName]; tag.Type {
+	case nbt.TAG_Int:
+		return BlockID(tag.Payload.(int32)), true
+	case nbt.TAG_Short:
+		return BlockID(tag.Payload.(int16)), true
+	case nbt.TAG_Byte:
+		return BlockID(tag.Payload.(byte)), true
+	}
+	return 0, false
+}
+
+// upgradeTileEntityNBT converts the items, the custom name and the spawned entities of a tile entity to the format of Minecraft 1.13.
+func upgradeTileEntityNBT(te nbt.TagCompound) {
+	if name, err := te.GetString("CustomName"); err == nil && name != "" {
+		te["CustomName"] = nbt.Tag{nbt.TAG_String, jsonText(name)}
+	}
+
+	if id, _ := te.GetString("id"); id == "minecraft:banner" {
+		upgradeBannerPatterns(te)
+	}
+
+	// Spawners
+	if id, err := te.GetString("EntityId"); err == nil {
+		if _, err := te.GetCompound("SpawnData"); err != nil {
+			te["SpawnData"] = nbt.Tag{nbt.TAG_Compound, nbt.TagCompound{"id": nbt.Tag{nbt.TAG_String, id}}}
+		}
+		delete(te, "EntityId")
+	}
+	if data, err := te.GetCompound("SpawnData"); err == nil {
+		upgradeEntityCompound(data)
+	}
+	if potentials, err := te.GetList("SpawnPotentials"); err == nil && potentials.Type == nbt.TAG_Compound {
+		for _, elem := range potentials.Elems {
+			potential := elem.(nbt.TagCompound)
+			// Before Minecraft 1.9 the entity is stored as Type and Properties.
+			if props, err := potential.GetCompound("Properties"); err == nil {
+				if typ, err := potential.GetString("Type"); err == nil {
+					props["id"] = nbt.Tag{nbt.TAG_String, typ}
+				}
+				potential["Entity"] = nbt.Tag{nbt.TAG_Compound, props}
+				delete(potential, "Properties")
+				delete(potential, "Type")
+			}
+			if ent, err := potential.GetCompound("Entity"); err == nil {
+				upgradeEntityCompound(ent)
+			}
+		}
+	}
+
+	upgradeItems(te)
+}
+
+// upgradeBannerPatterns converts the colors of the patterns of a banner (or the BlockEntityTag of a banner or shield item)
+// from dye damage values to colors.
+func upgradeBannerPatterns(tc nbt.TagCompound) {
+	patterns, err := tc.GetList("Patterns")
+	if err != nil || patterns.Type != nbt.TAG_Compound {
+		return
+	}
+	for _, elem := range patterns.Elems {
+		pattern := elem.(nbt.TagCompound)
+		if col, err := pattern.GetInt("Color"); err == nil && col >= 0 && col <= 15 {
+			pattern["Color"] = nbt.NewIntTag(15 - col)
+		}
+	}
+}
+
+// upgradeItems converts all item stacks in the compound tc and its nested compounds and lists
+// (container contents, equipment, trades, shulker boxes in item form, ...) to the format of Minecraft 1.13.
+func upgradeItems(tc nbt.TagCompound) {
+	if isLegacyItem(tc) {
+		upgradeItem(tc)
+	}
+	for _, tag := range tc {
+		upgradeItemsIn(tag)
+	}
+}
+
+func upgradeItemsIn(tag nbt.Tag) {
+	switch tag.Type {
+	case nbt.TAG_Compound:
+		upgradeItems(tag.Payload.(nbt.TagCompound))
+	case nbt.TAG_List:
+		list := tag.Payload.(nbt.TagList)
+		switch list.Type {
+		case nbt.TAG_Compound:
+			for _, elem := range list.Elems {
+				upgradeItems(elem.(nbt.TagCompound))
+			}
+		case nbt.TAG_List:
+			for _, elem := range list.Elems {
+				upgradeItemsIn(nbt.Tag{nbt.TAG_List, elem})
+			}
+		}
+	}
+}
+
+// isLegacyItem checks, if tc is an item stack from a version before Minecraft 1.13 (id, Count and Damage tags).
+func isLegacyItem(tc nbt.TagCompound) bool {
+	if _, err := tc.GetByte("Count"); err != nil {
+		return false
+	}
+	if _, err := tc.GetShort("Damage"); err != nil {
+		return false
+	}
+	typ := tc["id"].Type
+	return typ == nbt.TAG_String || typ == nbt.TAG_Short
+}
+
+// upgradeItem converts an item stack to the format of Minecraft 1.13 in place. The tags of tc are replaced.
+func upgradeItem(tc nbt.TagCompound) {
+	it, err := ItemStackFromNBT(tc)
+	if err != nil || it.flattened {
+		return
+	}
+	if id, variant := flattenItemID(it); id != "" {
+		it.ID = id
+		if variant {
+			it.Damage = 0
+		}
+	}
+	if strings.HasSuffix(it.ID, "_banner") || it.Is("shield") {
+		if bet, err := it.Tag.GetCompound("BlockEntityTag"); err == nil {
+			upgradeBannerPatterns(bet)
+		}
+	}
+	if it.Is("filled_map") {
+		it.setTag("map", nbt.NewIntTag(int32(it.Damage)))
+		it.Damage = 0
+	}
+	if display, err := it.Tag.GetCompound("display"); err == nil {
+		if name, err := display.GetString("Name"); err == nil {
+			display = copyCompound(display)
+			display["Name"] = nbt.Tag{nbt.TAG_String, jsonText(name)}
+			it.setTag("display", nbt.Tag{nbt.TAG_Compound, display})
+		}
+	}
+	it.flattened = true
+
+	upgraded := it.toNBT()
+	for name := range tc {
+		if _, ok := upgraded[name]; !ok {
+			delete(tc, name)
+		}
+	}
+	for name, tag := range upgraded {
+		tc[name] = tag
+	}
+}
+
+// Item IDs from 256 on, used before Minecraft 1.8.
+var legacyItemNames = []string{
+	"iron_shovel", "iron_pickaxe", "iron_axe", "flint_and_steel", "apple", "bow", "arrow", "coal", "diamond", "iron_ingot",
+	"gold_ingot", "iron_sword", "wooden_sword", "wooden_shovel", "wooden_pickaxe", "wooden_axe", "stone_sword", "stone_shovel",
+	"stone_pickaxe", "stone_axe", "diamond_sword", "diamond_shovel", "diamond_pickaxe", "diamond_axe", "stick", "bowl",
+	"mushroom_stew", "golden_sword", "golden_shovel", "golden_pickaxe", "golden_axe", "string", "feather", "gunpowder",
+	"wooden_hoe", "stone_hoe", "iron_hoe", "diamond_hoe", "golden_hoe", "wheat_seeds", "wheat", "bread", "leather_helmet",
+	"leather_chestplate", "leather_leggings", "leather_boots", "chainmail_helmet", "chainmail_chestplate", "chainmail_leggings",
+	"chainmail_boots", "iron_helmet", "iron_chestplate", "iron_leggings", "iron_boots", "diamond_helmet", "diamond_chestplate",
+	"diamond_leggings", "diamond_boots", "golden_helmet", "golden_chestplate", "golden_leggings", "golden_boots", "flint",
+	"porkchop", "cooked_porkchop", "painting", "golden_apple", "sign", "wooden_door", "bucket", "water_bucket", "lava_bucket",
+	"minecart", "saddle", "iron_door", "redstone", "snowball", "boat", "leather", "milk_bucket", "brick", "clay_ball", "reeds",
+	"paper", "book", "slime_ball", "chest_minecart", "furnace_minecart", "egg", "compass", "fishing_rod", "clock",
+	"glowstone_dust", "fish", "cooked_fish", "dye", "bone", "sugar", "cake", "bed", "repeater", "cookie", "filled_map", "shears",
+	"melon", "pumpkin_seeds", "melon_seeds", "beef", "cooked_beef", "chicken", "cooked_chicken", "rotten_flesh", "ender_pearl",
+	"blaze_rod", "ghast_tear", "gold_nugget", "nether_wart", "potion", "glass_bottle", "spider_eye", "fermented_spider_eye",
+	"blaze_powder", "magma_cream", "brewing_stand", "cauldron", "ender_eye", "speckled_melon", "spawn_egg", "experience_bottle",
+	"fire_charge", "writable_book", "written_book", "emerald", "item_frame", "flower_pot", "carrot", "potato", "baked_potato",
+	"poisonous_potato", "map", "golden_carrot", "skull", "carrot_on_a_stick", "nether_star", "pumpkin_pie", "fireworks",
+	"firework_charge", "enchanted_book", "comparator", "netherbrick", "quartz", "tnt_minecart", "hopper_minecart",
+	"prismarine_shard", "prismarine_crystals", "rabbit", "cooked_rabbit", "rabbit_stew", "rabbit_foot", "rabbit_hide",
+	"armor_stand", "iron_horse_armor", "golden_horse_armor", "diamond_horse_armor", "lead", "name_tag", "command_block_minecart",
+	"mutton", "cooked_mutton", "banner", "end_crystal", "spruce_door", "birch_door", "jungle_door", "acacia_door",
+	"dark_oak_door", "chorus_fruit", "chorus_fruit_popped", "beetroot", "beetroot_seeds", "beetroot_soup", "dragon_breath",
+	"splash_potion", "spectral_arrow", "tipped_arrow", "lingering_potion", "shield", "elytra", "spruce_boat", "birch_boat",
+	"jungle_boat", "acacia_boat", "dark_oak_boat", "totem_of_undying", "shulker_shell", "", "iron_nugget", "knowledge_book",
+}
+
+// Music discs (item IDs from 2256 on), used before Minecraft 1.8.
+var legacyRecordNames = []string{"13", "cat", "blocks", "chirp", "far", "mall", "mellohi", "stal", "strad", "ward", "11", "wait"}
+
+// Item IDs of Minecraft 1.12 (without a damage value) that were renamed in 1.13.
+var renamedItemIDs = map[string]string{
+	"boat":                "oak_boat",
+	"chorus_fruit_popped": "popped_chorus_fruit",
+	"firework_charge":     "firework_star",
+	"fireworks":           "firework_rocket",
+	"melon":               "melon_slice",
+	"netherbrick":         "nether_brick",
+	"speckled_melon":      "glistering_melon_slice",
+}
+
+var (
+	fishNames       = []string{"cod", "salmon", "tropical_fish", "pufferfish"}
+	cookedFishNames = []string{"cooked_cod", "cooked_salmon"}
+	dyeNames        = []string{"ink_sac", "rose_red", "cactus_green", "cocoa_beans", "lapis_lazuli", "purple_dye", "cyan_dye",
+		"light_gray_dye", "gray_dye", "pink_dye", "lime_dye", "dandelion_yellow", "light_blue_dye", "magenta_dye", "orange_dye", "bone_meal"}
+)
+
+func pickVariant(names []string, damage int) string {
+	if damage >= 0 && damage < 256 {
+		return pick(names, byte(damage))
+	}
+	return ""
+}
+
+// flattenItemID returns the item ID of Minecraft 1.13 for an item stack of an older version.
+// variant is true, if the damage value of the item was part of the item type (e.g. the wool color).
+// An empty id is returned for unknown items.
+func flattenItemID(it *ItemStack) (id string, variant bool) {
+	var name string
+	switch {
+	case it.ID != "":
+		name = strings.TrimPrefix(NormalizeBlockName(it.ID), "minecraft:")
+	case it.NumericID >= 0 && it.NumericID < 256:
+		name, _ = DefaultBlocks.Name(BlockID(it.NumericID))
+		name = strings.TrimPrefix(name, "minecraft:")
+	case it.NumericID >= 256 && int(it.NumericID)-256 < len(legacyItemNames):
+		name = legacyItemNames[it.NumericID-256]
+	case it.NumericID >= 2256 && int(it.NumericID)-2256 < len(legacyRecordNames):
+		name = "record_" + legacyRecordNames[it.NumericID-2256]
+	}
+	if name == "" {
+		return "", false
+	}
+
+	d := it.Damage
+	newName := ""
+	switch name {
+	case "coal":
+		if d == 1 {
+			newName = "charcoal"
+		}
+	case "golden_apple":
+		if d == 1 {
+			newName = "enchanted_golden_apple"
+		}
+	case "fish":
+		newName = pickVariant(fishNames, d)
+	case "cooked_fish":
+		newName = pickVariant(cookedFishNames, d)
+	case "dye":
+		newName = pickVariant(dyeNames, d)
+	case "bed":
+		if c := pickVariant(colorNames[:], d); c != "" {
+			newName = c + "_bed"
+		}
+	case "banner":
+		if d >= 0 && d <= 15 {
+			newName = Color(15-d).String() + "_banner" // The damage value is a dye damage value.
+		}
+	case "skull":
+		newName = pickVariant(skullNames, d)
+	case "spawn_egg":
+		return spawnEggID(it), true
+	case "anvil":
+		if d >= 0 && d <= 2 {
+			d <<= 2 // The damage state of the block is stored in the upper bits.
+		}
+	}
+	if newName != "" {
+		return "minecraft:" + newName, true
+	}
+
+	if blkID, ok := DefaultBlocks.ID("minecraft:" + name); ok && blkID <= 0xff {
+		if bs := legacyBlockState(blkID, byte(d)); bs != nil && d >= 0 && d <= 15 {
+			return bs.Name, true
+		}
+		if bs := legacyBlockState(blkID, 0); bs != nil {
+			return bs.Name, true
+		}
+	}
+
+	if renamed, ok := renamedItemIDs[name]; ok {
+		name = renamed
+	} else if strings.HasPrefix(name, "record_") {
+		name = "music_disc_" + strings.TrimPrefix(name, "record_")
+	}
+	return "minecraft:" + name, false
+}
+
+// spawnEggID returns the item ID of a spawn egg of Minecraft 1.13 (e.g. "minecraft:zombie_spawn_egg").
+// The entity type is taken from the EntityTag (Minecraft 1.9+) or the damage value.
+func spawnEggID(it *ItemStack) string {
+	var id string
+	if ent, err := it.Tag.GetCompound("EntityTag"); err == nil {
+		if id, err = ent.GetString("id"); err == nil {
+			id = upgradeEntityID(id, nil)
+			ent["id"] = nbt.Tag{nbt.TAG_String, id}
+		}
+	}
+	if id == "" {
+		name, ok := numericEntityIDs[it.Damage]
+		if !ok {
+			return "minecraft:pig_spawn_egg"
+		}
+		id = upgradeEntityID(name, nil)
+	}
+	return id + "_spawn_egg"
+}
@@ -0,0 +1,134 @@
+package mcmap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/silvasur/gonbt/nbt"
+	"os"
+	"path/filepath"
+)
+
+// Dimension IDs of the vanilla dimensions.
+const (
+	DimNether    = -1
+	DimOverworld = 0
+	DimEnd       = 1
+)
+
+// World is a Minecraft save directory, consisting of the level.dat file and the region directories of the dimensions.
+type World struct {
+	path     string
+	autosave bool
+
+	// Level is the root compound of level.dat. Game settings are in the "Data" compound.
+	Level nbt.TagCompound
+
+	// Blocks contains the block names of this world. For modded (Forge) worlds, this includes the block IDs assigned by Forge.
+	Blocks *BlockRegistry
+
+	modded  bool
+	regions map[int]*Region
+}
+
+// OpenWorld opens the world at path. The autosave parameter is passed to OpenRegion, when opening the regions.
+func OpenWorld(path string, autosave bool) (*World, error) {
+	w := &World{
+		path:     path,
+		autosave: autosave,
+		regions:  make(map[int]*Region),
+	}
+
+	f, err := os.Open(filepath.Join(path, "level.dat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	root, _, err := nbt.ReadGzipdNamedTag(f)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read level.dat: %s", err)
+	}
+	if root.Type != nbt.TAG_Compound {
+		return nil, errors.New("Root tag of level.dat is not a TAG_Compound")
+	}
+	w.Level = root.Payload.(nbt.TagCompound)
+
+	forgeIDs, err := ReadForgeBlockIDs(w.Level)
+	switch err {
+	case nil:
+		w.modded = true
+		w.Blocks = DefaultBlocks.Clone()
+		for name, id := range forgeIDs {
+			w.Blocks.Register(id, name)
+		}
+	case NotAvailable:
+		w.Blocks = DefaultBlocks
+	default:
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Path returns the path of the world directory.
+func (w *World) Path() string { return w.path }
+
+// Modded tells, if the world contains a Forge block ID mapping.
+func (w *World) Modded() bool { return w.modded }
+
+// DataVersion returns the data version of the Minecraft version that last saved the world (0 for versions before 1.9).
+func (w *World) DataVersion() int32 {
+	data, err := w.Level.GetCompound("Data")
+	if err != nil {
+		return 0
+	}
+	dataVersion, err := data.GetInt("DataVersion")
+	if err != nil {
+		return 0
+	}
+	return dataVersion
+}
+
+func (w *World) dimPath(dim int) string {
+	if dim == DimOverworld {
+		return w.path
+	}
+	return filepath.Join(w.path, fmt.Sprintf("DIM%d", dim))
+}
+
+// Region returns the region of a dimension (see the Dim* constants, mods can add other dimension IDs).
+// The regions will use the block registry of the world.
+func (w *World) Region(dim int) (*Region, error) {
+	if reg, ok := w.regions[dim]; ok {
+		return reg, nil
+	}
+
+	reg, err := OpenRegion(filepath.Join(w.dimPath(dim), "region"), w.autosave)
+	if err != nil {
+		return nil, err
+	}
+	reg.blocks = w.Blocks
+
+	dataVersion := w.DataVersion()
+	minY, height := 0, ChunkSizeY
+	if dataVersion >= dataVersionNoLevel && dim == DimOverworld {
+		minY, height = overworldMinY118, overworldSizeY118
+	}
+	reg.SetChunkFormat(dataVersion, minY, height)
+	if dataVersion >= dataVersionFlattening {
+		reg.SetEntityAndPOIFolders(filepath.Join(w.dimPath(dim), "entities"), filepath.Join(w.dimPath(dim), "poi"))
+	}
+
+	w.regions[dim] = reg
+	return reg, nil
+}
+
+// Save saves all opened regions.
+func (w *World) Save() error {
+	for _, reg := range w.regions {
+		if err := reg.Save(); err != nil {
+			return err
+		}
+	}
+	return nil
+}